@@ -4,8 +4,10 @@ import (
 	"context"
 	"log/slog"
 	"slices"
+	"strings"
 	"time"
 
+	"clockey/app/liquipedia"
 	"clockey/database/sqlc"
 
 	"github.com/disgoorg/disgo"
@@ -14,16 +16,17 @@ import (
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/gateway"
-	"github.com/disgoorg/snowflake/v2"
+	"github.com/disgoorg/disgo/handler"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func New(cfg Config, version string, commit string, db Database) *Bot {
 	return &Bot{
-		Cfg:     cfg,
-		Version: version,
-		Commit:  commit,
-		DB:      db,
+		Cfg:        cfg,
+		Version:    version,
+		Commit:     commit,
+		DB:         db,
+		Liquipedia: liquipedia.New(cfg.Liquipedia, version),
 	}
 }
 
@@ -33,16 +36,17 @@ type Database struct {
 }
 
 type Bot struct {
-	Cfg     Config
-	Client  *bot.Client
-	Version string
-	Commit  string
-	DB      Database
+	Cfg        Config
+	Client     *bot.Client
+	Version    string
+	Commit     string
+	DB         Database
+	Liquipedia *liquipedia.Client
 }
 
 func (b *Bot) SetupBot(listeners ...bot.EventListener) error {
 	client, err := disgo.New(b.Cfg.Bot.Token,
-		bot.WithGatewayConfigOpts(gateway.WithIntents(gateway.IntentGuilds, gateway.IntentGuildMessages, gateway.IntentMessageContent, gateway.IntentGuildMembers, gateway.IntentGuildPresences)),
+		bot.WithGatewayConfigOpts(gateway.WithIntents(gateway.IntentGuilds, gateway.IntentGuildMessages, gateway.IntentMessageContent, gateway.IntentGuildMembers, gateway.IntentGuildPresences, gateway.IntentGuildModeration)),
 		bot.WithCacheConfigOpts(cache.WithCaches(cache.FlagGuilds|cache.FlagMembers)),
 		bot.WithEventListeners(listeners...),
 	)
@@ -74,6 +78,60 @@ func (b *Bot) OnCommand(e *events.ApplicationCommandInteractionCreate) {
 	)
 }
 
+// componentCommands maps the first segment of component and modal custom IDs
+// to the command whose roles gate them, when it isn't the command name itself.
+var componentCommands = map[string]string{
+	"award":  "resolve",
+	"cancel": "Cancel Event",
+	"draft":  "event",
+	"roll":   "Roll Gardener",
+	"spam":   "warn",
+}
+
+// RequireRoles denies commands configured in [permissions], and the components
+// and modals belonging to them, to members without one of the allowed roles.
+func (b *Bot) RequireRoles(next handler.Handler) handler.Handler {
+	return func(e *handler.InteractionEvent) error {
+		var name string
+		switch interaction := e.Interaction.(type) {
+		case discord.ApplicationCommandInteraction:
+			name = interaction.Data.CommandName()
+		case discord.ComponentInteraction:
+			name = customIDCommand(interaction.Data.CustomID())
+		case discord.ModalSubmitInteraction:
+			name = customIDCommand(interaction.Data.CustomID)
+		default:
+			return next(e)
+		}
+		roles, ok := b.Cfg.Permissions[name]
+		if !ok {
+			return next(e)
+		}
+
+		if member := e.Member(); member != nil {
+			for _, role := range roles {
+				if slices.Contains(member.RoleIDs, role) {
+					return next(e)
+				}
+			}
+		}
+
+		slog.Warn("Command denied", slog.String("command", name), slog.Any("user", e.User().ID), slog.String("username", e.User().Username))
+		return e.CreateMessage(discord.MessageCreate{
+			Content: "You are not allowed to use this command",
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
+
+func customIDCommand(customID string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(customID, "/"), "/")
+	if name, ok := componentCommands[segment]; ok {
+		return name
+	}
+	return segment
+}
+
 func (b *Bot) OnModal(m *events.ModalSubmitInteractionCreate) {
 	slog.LogAttrs(
 		context.Background(),
@@ -88,26 +146,3 @@ func (b *Bot) OnModal(m *events.ModalSubmitInteractionCreate) {
 		),
 	)
 }
-
-func (b *Bot) OnMessageCreate(e *events.MessageCreate) {
-	// Honeypot: automatically ban any user who posts in the designated channel
-	if e.ChannelID == snowflake.ID(1459863214434287798) {
-		gardenerRoleID := snowflake.ID(720253636797530203)
-		if slices.Contains(e.Message.Member.RoleIDs, gardenerRoleID) {
-			return
-		}
-		err := e.Client().Rest.AddBan(*e.GuildID, e.Message.Author.ID, time.Duration(168*time.Hour))
-		if err != nil {
-			slog.Error("failed to ban user", "error", err)
-		}
-
-		slog.LogAttrs(
-			context.Background(),
-			slog.LevelInfo,
-			"scam & bot detected",
-			slog.String("user", e.Message.Author.Username),
-			slog.String("content", e.Message.Content),
-		)
-	}
-
-}
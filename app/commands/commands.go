@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"clockey/app/commands/moderation"
 	"clockey/app/commands/predictions"
 	"clockey/app/commands/signups"
 	"clockey/app/commands/utils"
@@ -15,16 +16,23 @@ var Commands = []discord.ApplicationCommandCreate{
 	signups.Event,
 	signups.Gardener,
 	signups.Manual,
+	signups.MyHours,
+	signups.Rate,
 	signups.Report,
+	signups.Roster,
 
 	// Predictions
-	predictions.Add,
 	predictions.BestOf,
-	predictions.DeleteBestOf,
 	predictions.Reset,
+	predictions.Resolve,
 	predictions.Show,
 	predictions.Winners,
 
+	// Moderation
+	moderation.Case,
+	moderation.Raid,
+	moderation.Warn,
+
 	// Utils
 	utils.Util,
 
@@ -0,0 +1,231 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var Warn = discord.SlashCommandCreate{
+	Name:                     "warn",
+	Description:              "Warn a member and record it in their cases",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionModerateMembers),
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionUser{
+			Name:        "user",
+			Description: "The member to warn",
+			Required:    true,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "reason",
+			Description: "Why the member is warned, they are sent this",
+			Required:    true,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "evidence",
+			Description: "Message links or other evidence, only shown to moderators",
+			Required:    false,
+		},
+	},
+}
+
+var Case = discord.SlashCommandCreate{
+	Name:                     "case",
+	Description:              "Look up moderation cases",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionModerateMembers),
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "view",
+			Description: "Show a case",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionInt{
+					Name:        "id",
+					Description: "The case number",
+					Required:    true,
+				},
+			},
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "list",
+			Description: "List the cases of a member",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionUser{
+					Name:        "user",
+					Description: "The member to list cases for",
+					Required:    true,
+				},
+			},
+		},
+	},
+}
+
+func WarnCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		user := data.User("user")
+		reason := strings.TrimSpace(data.String("reason"))
+		evidence, _ := data.OptString("evidence")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		modCase, err := createCase(ctx, b.DB.Queries, *e.GuildID(), user.ID, "warn", e.User().ID, reason, evidence)
+		if err != nil {
+			slog.Error("failed to create mod case", slog.Any("user", user.ID), slog.Any("err", err))
+			return err
+		}
+
+		content := fmt.Sprintf("Case %d: %s has been warned", modCase.ID, user.Mention())
+		dm, err := e.Client().Rest.CreateDMChannel(user.ID)
+		if err == nil {
+			_, err = e.Client().Rest.CreateMessage(dm.ID(), discord.MessageCreate{
+				Content: "You have been warned by the moderators: " + reason,
+			})
+		}
+		if err != nil {
+			slog.Warn("failed to DM warning", slog.Any("user", user.ID), slog.Any("err", err))
+			content += ", but they could not be DMed"
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         content,
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+func CaseViewCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		id := data.Int("id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		modCase, err := b.DB.Queries.GetModCase(ctx, int64(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: fmt.Sprintf("Case %d does not exist", id),
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get mod case", slog.Int("case", id), slog.Any("err", err))
+			return err
+		}
+
+		// Reason and evidence are free text, keep both within the message limit
+		content := fmt.Sprintf("# Case %d - %s\n**User:** <@%d>\n**Moderator:** %s\n**Reason:** %s\n", modCase.ID, modCase.Action, modCase.UserID, moderatorLabel(modCase), truncate(modCase.Reason, 800))
+		if modCase.Evidence.Valid {
+			content += "**Evidence:** " + truncate(modCase.Evidence.String, 800) + "\n"
+		}
+		content += fmt.Sprintf("<t:%d:f>", modCase.CreatedAt)
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         content,
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+func CaseListCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		user := data.User("user")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		cases, err := b.DB.Queries.ListModCasesForUser(ctx, int64(user.ID))
+		if err != nil {
+			slog.Error("failed to list mod cases", slog.Any("user", user.ID), slog.Any("err", err))
+			return err
+		}
+
+		content := fmt.Sprintf("No cases for %s", user.Mention())
+		if len(cases) > 0 {
+			content = fmt.Sprintf("# Cases for %s\n", user.Username)
+			for i, modCase := range cases {
+				// Keep well within the message limit, /case view shows the rest
+				if i == 20 {
+					content += fmt.Sprintf("... and %d older cases", len(cases)-i)
+					break
+				}
+				content += fmt.Sprintf("`%d` %s <t:%d:d> by %s - %s\n", modCase.ID, modCase.Action, modCase.CreatedAt, moderatorLabel(modCase), truncate(modCase.Reason, 80))
+			}
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         content,
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+// BanListener records bans and unbans made outside the bot, such as from
+// Discord's member list. The bot records its own actions when it takes them.
+func BanListener(b *app.Bot) func(e *events.GuildAuditLogEntryCreate) {
+	return func(e *events.GuildAuditLogEntryCreate) {
+		entry := e.AuditLogEntry
+		action := map[discord.AuditLogEvent]string{
+			discord.AuditLogEventMemberBanAdd:    "ban",
+			discord.AuditLogEventMemberBanRemove: "unban",
+		}[entry.ActionType]
+		if action == "" || entry.TargetID == nil || entry.UserID == e.Client().ID() {
+			return
+		}
+
+		reason := "No reason given"
+		if entry.Reason != nil && *entry.Reason != "" {
+			reason = *entry.Reason
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := createCase(ctx, b.DB.Queries, e.GuildID, *entry.TargetID, action, entry.UserID, reason, ""); err != nil {
+			slog.Error("failed to create mod case", slog.Any("user", *entry.TargetID), slog.Any("err", err))
+		}
+	}
+}
+
+// createCase records an action against a member. Automated actions pass a
+// zero moderator.
+func createCase(ctx context.Context, queries *sqlc.Queries, guildID snowflake.ID, userID snowflake.ID, action string, moderatorID snowflake.ID, reason string, evidence string) (sqlc.ModCase, error) {
+	return queries.CreateModCase(ctx, sqlc.CreateModCaseParams{
+		GuildID:     int64(guildID),
+		UserID:      int64(userID),
+		Action:      action,
+		ModeratorID: pgtype.Int8{Int64: int64(moderatorID), Valid: moderatorID != 0},
+		Reason:      reason,
+		Evidence:    pgtype.Text{String: evidence, Valid: evidence != ""},
+		CreatedAt:   time.Now().Unix(),
+	})
+}
+
+func moderatorLabel(modCase sqlc.ModCase) string {
+	if !modCase.ModeratorID.Valid {
+		return "Automated"
+	}
+	return fmt.Sprintf("<@%d>", modCase.ModeratorID.Int64)
+}
+
+func truncate(s string, length int) string {
+	if runes := []rune(s); len(runes) > length {
+		return string(runes[:length]) + "…"
+	}
+	return s
+}
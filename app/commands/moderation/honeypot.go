@@ -0,0 +1,76 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"clockey/app"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+const honeypotReason = "Posted in a honeypot channel"
+
+// Discord deletes at most a week of messages when banning
+const maxDeleteMessageDuration = 7 * 24 * time.Hour
+
+// HoneypotListener bans anyone without an exempt role posting in a honeypot.
+func HoneypotListener(b *app.Bot) func(e *events.MessageCreate) {
+	return func(e *events.MessageCreate) {
+		cfg := b.Cfg.Honeypot
+		if e.GuildID == nil || e.Message.Author.Bot || !slices.Contains(cfg.Channels, e.ChannelID) {
+			return
+		}
+		if hasAnyRole(e.Message.Member, cfg.ExemptRoles) {
+			return
+		}
+
+		author := e.Message.Author
+		title, footer := "Honeypot ban", ""
+		if cfg.DryRun {
+			title = "Honeypot ban (dry run)"
+		} else {
+			deleteMessages := min(time.Duration(cfg.DeleteMessageSeconds)*time.Second, maxDeleteMessageDuration)
+			if err := e.Client().Rest.AddBan(*e.GuildID, author.ID, deleteMessages, rest.WithReason(honeypotReason)); err != nil {
+				slog.Error("DisGo error(failed to ban user)", slog.Any("user", author.ID), slog.Any("err", err))
+				title = "Honeypot ban failed"
+			} else {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				evidence := discord.ChannelMention(e.ChannelID) + ": " + truncate(e.Message.Content, 1000)
+				if modCase, err := createCase(ctx, b.DB.Queries, *e.GuildID, author.ID, "ban", 0, honeypotReason, evidence); err != nil {
+					slog.Error("failed to create mod case", slog.Any("user", author.ID), slog.Any("err", err))
+				} else {
+					footer = fmt.Sprintf("Case %d", modCase.ID)
+				}
+			}
+		}
+
+		slog.Info("Honeypot triggered",
+			slog.Any("user", author.ID),
+			slog.String("username", author.Username),
+			slog.Any("channel", e.ChannelID),
+			slog.Bool("dry_run", cfg.DryRun),
+			slog.String("content", e.Message.Content),
+		)
+
+		embed := discord.NewEmbedBuilder().
+			SetTitle(title).
+			SetColor(0xED4245).
+			AddField("User", fmt.Sprintf("%s (%s, %s)", author.Mention(), author.Username, author.ID), false).
+			AddField("Account created", fmt.Sprintf("<t:%d:R>", author.CreatedAt().Unix()), true).
+			AddField("Channel", discord.ChannelMention(e.ChannelID), true).
+			AddField("Content", codeBlock(e.Message.Content), false).
+			SetFooterText(footer).
+			SetTimestamp(e.Message.CreatedAt).
+			Build()
+		if err := postModLog(b, embed); err != nil {
+			slog.Error("DisGo error(failed to post to mod-log)", slog.Any("user", author.ID), slog.Any("err", err))
+		}
+	}
+}
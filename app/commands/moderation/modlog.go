@@ -0,0 +1,45 @@
+package moderation
+
+import (
+	"slices"
+	"strings"
+
+	"clockey/app"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// postModLog sends an embed to the mod-log channel, if one is configured.
+func postModLog(b *app.Bot, embed discord.Embed, components ...discord.LayoutComponent) error {
+	if b.Cfg.Moderation.ModLogChannel == 0 {
+		return nil
+	}
+	_, err := b.Client.Rest.CreateMessage(b.Cfg.Moderation.ModLogChannel, discord.MessageCreate{
+		Embeds:          []discord.Embed{embed},
+		Components:      components,
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	return err
+}
+
+// codeBlock quotes message content for an embed field, which holds at most
+// 1024 characters.
+func codeBlock(content string) string {
+	if content == "" {
+		return "*No text content*"
+	}
+	content = strings.ReplaceAll(content, "```", "`\u200b``")
+	return "```\n" + truncate(content, 1000) + "\n```"
+}
+
+// hasAnyRole reports whether the member has one of the roles. Members missing
+// from the event are treated as having none.
+func hasAnyRole(member *discord.Member, roles []snowflake.ID) bool {
+	if member == nil {
+		return false
+	}
+	return slices.ContainsFunc(member.RoleIDs, func(roleID snowflake.ID) bool {
+		return slices.Contains(roles, roleID)
+	})
+}
@@ -0,0 +1,336 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var Raid = discord.SlashCommandCreate{
+	Name:                     "raid",
+	Description:              "Manage raid lockdowns",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionModerateMembers),
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "end",
+			Description: "End the active lockdown",
+		},
+	},
+}
+
+// Discord pauses invites for at most a day
+const maxInvitesPause = 24 * time.Hour
+
+const lockdownReason = "Joined during a raid lockdown"
+
+// RaidListener starts a lockdown on a burst of joins and reports new accounts.
+func RaidListener(b *app.Bot) func(e *events.GuildMemberJoin) {
+	return func(e *events.GuildMemberJoin) {
+		cfg := b.Cfg.Raid
+		user := e.Member.User
+		if cfg.Joins <= 0 && cfg.MinAccountAge <= 0 || user.Bot {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		lockdown, err := b.DB.Queries.GetActiveLockdown(ctx, int64(e.GuildID))
+		if err == nil {
+			if _, err := timeoutNewcomer(ctx, b, e.GuildID, user.ID); err != nil {
+				slog.Error("failed to time out newcomer", slog.Any("user", user.ID), slog.Int64("lockdown", lockdown.ID), slog.Any("err", err))
+			}
+			return
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("failed to get active lockdown", slog.Any("guild", e.GuildID), slog.Any("err", err))
+			return
+		}
+
+		if cfg.Joins > 0 {
+			since := time.Now().Add(-time.Duration(cfg.Window) * time.Second).Unix()
+			if err := b.DB.Queries.PruneMemberJoins(ctx, since); err != nil {
+				slog.Error("failed to prune member joins", slog.Any("err", err))
+			}
+			if err := b.DB.Queries.TrackMemberJoin(ctx, sqlc.TrackMemberJoinParams{
+				GuildID:  int64(e.GuildID),
+				UserID:   int64(user.ID),
+				JoinedAt: time.Now().Unix(),
+			}); err != nil {
+				slog.Error("failed to track member join", slog.Any("user", user.ID), slog.Any("err", err))
+				return
+			}
+
+			joins, err := b.DB.Queries.ListRecentJoins(ctx, sqlc.ListRecentJoinsParams{
+				GuildID: int64(e.GuildID),
+				Since:   since,
+			})
+			if err != nil {
+				slog.Error("failed to list recent joins", slog.Any("err", err))
+				return
+			}
+			if len(joins) >= cfg.Joins {
+				err := startLockdown(ctx, b, e.GuildID, since)
+				if errors.Is(err, errLockdownActive) {
+					// Another join started it after this one checked
+					if _, err := timeoutNewcomer(ctx, b, e.GuildID, user.ID); err != nil {
+						slog.Error("failed to time out newcomer", slog.Any("user", user.ID), slog.Any("err", err))
+					}
+				} else if err != nil {
+					slog.Error("failed to start lockdown", slog.Any("guild", e.GuildID), slog.Any("err", err))
+				}
+				return
+			}
+		}
+
+		minAge := time.Duration(cfg.MinAccountAge) * time.Second
+		if minAge > 0 && time.Since(user.CreatedAt()) < minAge {
+			slog.Info("New account joined", slog.Any("user", user.ID), slog.String("username", user.Username))
+			if err := postModLog(b, discord.NewEmbedBuilder().
+				SetTitle("New account joined").
+				SetColor(0xFEE75C).
+				AddField("User", fmt.Sprintf("%s (%s, %s)", user.Mention(), user.Username, user.ID), false).
+				AddField("Account created", fmt.Sprintf("<t:%d:R>", user.CreatedAt().Unix()), true).
+				SetTimestamp(time.Now()).
+				Build()); err != nil {
+				slog.Error("DisGo error(failed to post to mod-log)", slog.Any("user", user.ID), slog.Any("err", err))
+			}
+		}
+	}
+}
+
+var errLockdownActive = errors.New("lockdown already active")
+
+// startLockdown claims the lockdown first so it can always be ended.
+func startLockdown(ctx context.Context, b *app.Bot, guildID snowflake.ID, since int64) error {
+	guild, err := b.Client.Rest.GetGuild(guildID, false)
+	if err != nil {
+		return fmt.Errorf("failed to get guild: %w", err)
+	}
+
+	lockdown, err := b.DB.Queries.CreateLockdown(ctx, sqlc.CreateLockdownParams{
+		GuildID:           int64(guildID),
+		VerificationLevel: int32(guild.VerificationLevel),
+		StartedAt:         time.Now().Unix(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errLockdownActive
+	} else if err != nil {
+		return err
+	}
+
+	// Listed after the claim to include joins that raced it
+	joins, err := b.DB.Queries.ListRecentJoins(ctx, sqlc.ListRecentJoinsParams{
+		GuildID: int64(guildID),
+		Since:   since,
+	})
+	if err != nil {
+		slog.Error("failed to list recent joins", slog.Int64("lockdown", lockdown.ID), slog.Any("err", err))
+	}
+
+	verification := "Raised to high"
+	if guild.VerificationLevel >= discord.VerificationLevelHigh {
+		verification = "Already high or above"
+	} else if _, err := b.Client.Rest.UpdateGuild(guildID, discord.GuildUpdate{
+		VerificationLevel: omit.NewPtr(discord.VerificationLevelHigh),
+	}, rest.WithReason("Raid lockdown")); err != nil {
+		slog.Error("DisGo error(failed to raise verification level)", slog.Any("guild", guildID), slog.Any("err", err))
+		verification = "Failed to raise"
+	}
+
+	invitesUntil := time.Now().Add(maxInvitesPause)
+	invites := fmt.Sprintf("Paused until <t:%d:f>", invitesUntil.Unix())
+	if _, err := b.Client.Rest.UpdateGuildIncidentActions(guildID, discord.GuildIncidentActionsUpdate{
+		InvitesDisabledUntil: omit.NewPtr(invitesUntil),
+	}, rest.WithReason("Raid lockdown")); err != nil {
+		slog.Error("DisGo error(failed to pause invites)", slog.Any("guild", guildID), slog.Any("err", err))
+		invites = "Failed to pause"
+	}
+
+	var members []string
+	for _, join := range joins {
+		member := fmt.Sprintf("<@%d>", join.UserID)
+		// Each gets its own deadline, a large burst outlasts the listener's
+		joinCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if _, err := timeoutNewcomer(joinCtx, b, guildID, snowflake.ID(join.UserID)); err != nil {
+			slog.Error("failed to time out newcomer", slog.Int64("user", join.UserID), slog.Any("err", err))
+			member += " (not timed out)"
+		}
+		cancel()
+		members = append(members, member)
+	}
+
+	slog.Info("Raid detected", slog.Any("guild", guildID), slog.Int64("lockdown", lockdown.ID), slog.Int("joins", len(joins)))
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Raid detected, lockdown started").
+		SetColor(0xED4245).
+		AddField("Joins", fmt.Sprintf("%d in %d seconds", len(joins), b.Cfg.Raid.Window), true).
+		AddField("Verification level", verification, true).
+		AddField("Invites", invites, true).
+		SetFooterTextf("Lockdown %d, newcomers are timed out until it ends", lockdown.ID).
+		SetTimestamp(time.Unix(lockdown.StartedAt, 0))
+	if len(members) > 0 {
+		embed.AddField("Timed out", truncate(strings.Join(members, " "), 1000), false)
+	}
+	if err := postModLog(b, embed.Build(), discord.ActionRowComponent{
+		Components: []discord.InteractiveComponent{
+			discord.ButtonComponent{
+				Label:    "End lockdown",
+				Style:    discord.ButtonStyleDanger,
+				CustomID: fmt.Sprintf("/raid/%d/end", lockdown.ID),
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("lockdown %d started, but its card wasn't posted: %w", lockdown.ID, err)
+	}
+	return nil
+}
+
+func timeoutNewcomer(ctx context.Context, b *app.Bot, guildID snowflake.ID, userID snowflake.ID) (sqlc.ModCase, error) {
+	timeout := min(time.Duration(b.Cfg.Raid.Timeout)*time.Second, maxTimeout)
+	if _, err := b.Client.Rest.UpdateMember(guildID, userID, discord.MemberUpdate{
+		CommunicationDisabledUntil: omit.NewPtr(time.Now().Add(timeout)),
+	}, rest.WithReason(lockdownReason)); err != nil {
+		return sqlc.ModCase{}, err
+	}
+	return createCase(ctx, b.DB.Queries, guildID, userID, "timeout", 0, lockdownReason, "")
+}
+
+// RaidEndCommandHandler ends the active lockdown, for when its card is gone.
+func RaidEndCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		lockdown, err := b.DB.Queries.GetActiveLockdown(ctx, int64(*e.GuildID()))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "There is no active lockdown",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get active lockdown", slog.Any("guild", e.GuildID()), slog.Any("err", err))
+			return err
+		}
+
+		if err := endLockdown(ctx, b, lockdown, e.User()); errors.Is(err, errLockdownEnded) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This lockdown has already ended",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to end lockdown", slog.Int64("lockdown", lockdown.ID), slog.Any("err", err))
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Failed to end the lockdown, please try again",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         fmt.Sprintf("Lockdown %d ended by %s", lockdown.ID, e.User().Mention()),
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+// RaidComponentHandler ends a lockdown, timed out newcomers are left to mods.
+func RaidComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		lockdownID, err := strconv.ParseInt(e.Vars["lockdown"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		lockdown, err := b.DB.Queries.GetLockdown(ctx, lockdownID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("This lockdown no longer exists"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		} else if err != nil {
+			slog.Error("failed to get lockdown", slog.Int64("lockdown", lockdownID), slog.Any("err", err))
+			return err
+		}
+
+		if err := endLockdown(ctx, b, lockdown, e.User()); errors.Is(err, errLockdownEnded) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This lockdown has already ended",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to end lockdown", slog.Int64("lockdown", lockdown.ID), slog.Any("err", err))
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Failed to end the lockdown, please try again",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		return e.UpdateMessage(discord.MessageUpdate{
+			Content:         omit.Ptr("Lockdown ended by " + e.User().Mention()),
+			Components:      &[]discord.LayoutComponent{},
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+var errLockdownEnded = errors.New("lockdown already ended")
+
+// endLockdown restores the guild, the lockdown stays active if that fails.
+func endLockdown(ctx context.Context, b *app.Bot, lockdown sqlc.Lockdown, user discord.User) error {
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	ended, err := b.DB.Queries.WithTx(tx).EndLockdown(ctx, sqlc.EndLockdownParams{
+		ID:      lockdown.ID,
+		EndedAt: pgtype.Int8{Int64: time.Now().Unix(), Valid: true},
+		EndedBy: pgtype.Int8{Int64: int64(user.ID), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if ended == 0 {
+		return errLockdownEnded
+	}
+
+	guildID := snowflake.ID(lockdown.GuildID)
+	reason := rest.WithReason("Raid lockdown ended by " + user.Username)
+	if _, err := b.Client.Rest.UpdateGuild(guildID, discord.GuildUpdate{
+		VerificationLevel: omit.NewPtr(discord.VerificationLevel(lockdown.VerificationLevel)),
+	}, reason); err != nil {
+		return fmt.Errorf("failed to restore verification level: %w", err)
+	}
+	if _, err := b.Client.Rest.UpdateGuildIncidentActions(guildID, discord.GuildIncidentActionsUpdate{
+		InvitesDisabledUntil: omit.NewNilPtr[time.Time](),
+	}, reason); err != nil {
+		return fmt.Errorf("failed to resume invites: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
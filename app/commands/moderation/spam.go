@@ -0,0 +1,357 @@
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// Discord times out members for at most 28 days
+const maxTimeout = 28 * 24 * time.Hour
+
+// SpamListener flags members spamming, which usually means a compromised account.
+func SpamListener(b *app.Bot) func(e *events.MessageCreate) {
+	tracker := newSpamTracker()
+	return func(e *events.MessageCreate) {
+		cfg := b.Cfg.Spam
+		if cfg.Channels <= 0 && cfg.Messages <= 0 || e.GuildID == nil || e.Message.Author.Bot || strings.TrimSpace(e.Message.Content) == "" {
+			return
+		}
+		if hasAnyRole(e.Message.Member, cfg.ExemptRoles) {
+			return
+		}
+
+		copies, reason := tracker.track(cfg, e.Message.Author.ID, trackedMessage{
+			ID:        e.MessageID,
+			ChannelID: e.ChannelID,
+			Hash:      contentHash(e.Message.Content),
+			Content:   e.Message.Content,
+			CreatedAt: e.Message.CreatedAt,
+		}, time.Now())
+		if len(copies) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := flagSpam(ctx, b, *e.GuildID, e.Message.Author, copies, reason); err != nil {
+			slog.Error("failed to flag spam", slog.Any("user", e.Message.Author.ID), slog.Any("err", err))
+			// Flag them again on their next message
+			tracker.putBack(e.Message.Author.ID, copies)
+		}
+	}
+}
+
+// contentHash identifies the same content regardless of case and whitespace.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.Join(strings.Fields(content), " "))))
+	return hex.EncodeToString(sum[:])
+}
+
+type trackedMessage struct {
+	ID        snowflake.ID
+	ChannelID snowflake.ID
+	Hash      string
+	Content   string
+	CreatedAt time.Time
+}
+
+type spamTracker struct {
+	mu     sync.Mutex
+	recent map[snowflake.ID][]trackedMessage
+}
+
+func newSpamTracker() *spamTracker {
+	return &spamTracker{recent: make(map[snowflake.ID][]trackedMessage)}
+}
+
+// track returns the messages to flag once the author crosses a threshold.
+func (t *spamTracker) track(cfg app.SpamConfig, authorID snowflake.ID, message trackedMessage, now time.Time) ([]trackedMessage, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	since := now.Add(-time.Duration(cfg.Window) * time.Second)
+	for id, messages := range t.recent {
+		messages = slices.DeleteFunc(messages, func(m trackedMessage) bool {
+			return m.CreatedAt.Before(since)
+		})
+		if len(messages) == 0 {
+			delete(t.recent, id)
+		} else {
+			t.recent[id] = messages
+		}
+	}
+
+	messages := append(t.recent[authorID], message)
+	var duplicates []trackedMessage
+	channels := make(map[snowflake.ID]struct{})
+	for _, m := range messages {
+		if m.Hash == message.Hash {
+			duplicates = append(duplicates, m)
+			channels[m.ChannelID] = struct{}{}
+		}
+	}
+
+	var copies []trackedMessage
+	var reason string
+	switch {
+	case cfg.Channels > 0 && len(channels) >= cfg.Channels:
+		copies, reason = duplicates, fmt.Sprintf("Posted the same content in %d channels", len(channels))
+	case cfg.Messages > 0 && len(messages) >= cfg.Messages:
+		copies, reason = slices.Clone(messages), fmt.Sprintf("Posted %d messages in %d seconds", len(messages), cfg.Window)
+	default:
+		t.recent[authorID] = messages
+		return nil, ""
+	}
+
+	if messages = slices.DeleteFunc(messages, func(m trackedMessage) bool {
+		return slices.Contains(copies, m)
+	}); len(messages) == 0 {
+		delete(t.recent, authorID)
+	} else {
+		t.recent[authorID] = messages
+	}
+	return copies, reason
+}
+
+func (t *spamTracker) putBack(authorID snowflake.ID, copies []trackedMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recent[authorID] = append(t.recent[authorID], copies...)
+}
+
+// flagSpam stores the incident before acting on it, so an error means nothing
+// was done and the copies can be flagged again.
+func flagSpam(ctx context.Context, b *app.Bot, guildID snowflake.ID, author discord.User, copies []trackedMessage, reason string) error {
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	incident, err := b.DB.Queries.WithTx(tx).CreateSpamIncident(ctx, sqlc.CreateSpamIncidentParams{
+		GuildID:   int64(guildID),
+		AuthorID:  int64(author.ID),
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, message := range copies {
+		if err := b.DB.Queries.WithTx(tx).CreateSpamMessage(ctx, sqlc.CreateSpamMessageParams{
+			MessageID: int64(message.ID),
+			ChannelID: int64(message.ChannelID),
+			AuthorID:  int64(author.ID),
+			Content:   message.Content,
+			CreatedAt: message.CreatedAt.Unix(),
+			Incident:  incident.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	footer := fmt.Sprintf("Incident %d", incident.ID)
+	timeout := min(time.Duration(b.Cfg.Spam.Timeout)*time.Second, maxTimeout)
+	timedOut := fmt.Sprintf("<t:%d:f>", time.Now().Add(timeout).Unix())
+	if _, err := b.Client.Rest.UpdateMember(guildID, author.ID, discord.MemberUpdate{
+		CommunicationDisabledUntil: omit.NewPtr(time.Now().Add(timeout)),
+	}, rest.WithReason(reason)); err != nil {
+		slog.Error("DisGo error(failed to time out member)", slog.Any("user", author.ID), slog.Int64("incident", incident.ID), slog.Any("err", err))
+		timedOut = "Failed to time out"
+	} else if modCase, err := createCase(ctx, b.DB.Queries, guildID, author.ID, "timeout", 0, reason, truncate(copies[0].Content, 1000)); err != nil {
+		slog.Error("failed to create mod case", slog.Any("user", author.ID), slog.Int64("incident", incident.ID), slog.Any("err", err))
+	} else {
+		footer += fmt.Sprintf(", case %d", modCase.ID)
+	}
+
+	var channels []string
+	for _, message := range copies {
+		if err := b.Client.Rest.DeleteMessage(message.ChannelID, message.ID); err != nil && !rest.IsJSONErrorCode(err, rest.JSONErrorCodeUnknownMessage) {
+			slog.Error("DisGo error(failed to delete spam message)", slog.Any("message", message.ID), slog.Any("err", err))
+		}
+		if channel := discord.ChannelMention(message.ChannelID); !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	slog.Info("Spam detected", slog.Any("user", author.ID), slog.String("username", author.Username), slog.Int64("incident", incident.ID), slog.Int("copies", len(copies)))
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Spam detected").
+		SetColor(0xFEE75C).
+		AddField("User", fmt.Sprintf("%s (%s, %s)", author.Mention(), author.Username, author.ID), false).
+		AddField("Account created", fmt.Sprintf("<t:%d:R>", author.CreatedAt().Unix()), true).
+		AddField("Timed out until", timedOut, true).
+		AddField("Reason", reason, false).
+		AddField("Channels", truncate(strings.Join(channels, " "), 1000), false).
+		AddField("Content", codeBlock(copies[0].Content), false).
+		SetFooterText(footer).
+		Build()
+	if err := postModLog(b, embed, discord.ActionRowComponent{
+		Components: []discord.InteractiveComponent{
+			discord.ButtonComponent{
+				Label:    "Ban",
+				Style:    discord.ButtonStyleDanger,
+				CustomID: fmt.Sprintf("/spam/%d/ban", incident.ID),
+			},
+			discord.ButtonComponent{
+				Label:    "Unban & restore",
+				Style:    discord.ButtonStyleSecondary,
+				CustomID: fmt.Sprintf("/spam/%d/restore", incident.ID),
+			},
+		},
+	}); err != nil {
+		slog.Error("DisGo error(failed to post to mod-log)", slog.Int64("incident", incident.ID), slog.Any("err", err))
+	}
+	return nil
+}
+
+func SpamComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		incidentID, err := strconv.ParseInt(e.Vars["incident"], 10, 64)
+		if err != nil {
+			return err
+		}
+		action := e.Vars["action"]
+		status := map[string]string{"ban": "banned", "restore": "restored"}[action]
+		if status == "" {
+			return fmt.Errorf("unknown spam review action: %s", action)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		incident, err := b.DB.Queries.GetSpamIncident(ctx, incidentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("This incident no longer exists"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		} else if err != nil {
+			slog.Error("failed to get spam incident", slog.Int64("incident", incidentID), slog.Any("err", err))
+			return err
+		}
+
+		tx, err := b.DB.Conn.Begin(ctx)
+		if err != nil {
+			slog.Error("failed to begin transaction", slog.Any("err", err))
+			return err
+		}
+
+		defer func() {
+			if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+				slog.Error("failed to rollback transaction", slog.Any("err", err))
+			}
+		}()
+
+		claimed, err := b.DB.Queries.WithTx(tx).SetSpamIncidentStatus(ctx, sqlc.SetSpamIncidentStatusParams{
+			ID:     incident.ID,
+			Status: status,
+		})
+		if err != nil {
+			slog.Error("failed to set spam incident status", slog.Int64("incident", incident.ID), slog.Any("err", err))
+			return err
+		}
+		if claimed == 0 {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This incident has already been reviewed",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		guildID, authorID := snowflake.ID(incident.GuildID), snowflake.ID(incident.AuthorID)
+		var content string
+		switch action {
+		case "ban":
+			if err := e.Client().Rest.AddBan(guildID, authorID, 0, rest.WithReason("Spam, reviewed by "+e.User().Username)); err != nil {
+				slog.Error("DisGo error(failed to ban user)", slog.Any("user", authorID), slog.Any("err", err))
+				return e.CreateMessage(discord.MessageCreate{
+					Content: "Failed to ban the member, please try again",
+					Flags:   discord.MessageFlagEphemeral,
+				})
+			}
+			if _, err := createCase(ctx, b.DB.Queries.WithTx(tx), guildID, authorID, "ban", e.User().ID, "Spam", fmt.Sprintf("Spam incident %d", incident.ID)); err != nil {
+				slog.Error("failed to create mod case", slog.Any("user", authorID), slog.Any("err", err))
+				return err
+			}
+			content = "Banned by " + e.User().Mention()
+		case "restore":
+			if _, err := e.Client().Rest.UpdateMember(guildID, authorID, discord.MemberUpdate{
+				CommunicationDisabledUntil: omit.NewNilPtr[time.Time](),
+			}, rest.WithReason("Spam flag reverted by "+e.User().Username)); err != nil {
+				slog.Error("DisGo error(failed to remove timeout)", slog.Any("user", authorID), slog.Any("err", err))
+				return e.CreateMessage(discord.MessageCreate{
+					Content: "Failed to lift the timeout, please try again",
+					Flags:   discord.MessageFlagEphemeral,
+				})
+			}
+			if _, err := createCase(ctx, b.DB.Queries.WithTx(tx), guildID, authorID, "untimeout", e.User().ID, "Spam flag reverted", fmt.Sprintf("Spam incident %d", incident.ID)); err != nil {
+				slog.Error("failed to create mod case", slog.Any("user", authorID), slog.Any("err", err))
+				return err
+			}
+			content = "Timeout lifted and messages restored by " + e.User().Mention()
+			if !restoreSpamMessages(ctx, b, incident) {
+				content += "\nSome messages could not be restored"
+			}
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			slog.Error("failed to commit transaction", slog.Any("err", err))
+			return err
+		}
+
+		return e.UpdateMessage(discord.MessageUpdate{
+			Content:         omit.Ptr(content),
+			Components:      &[]discord.LayoutComponent{},
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+func restoreSpamMessages(ctx context.Context, b *app.Bot, incident sqlc.SpamIncident) bool {
+	copies, err := b.DB.Queries.ListSpamIncidentMessages(ctx, incident.ID)
+	if err != nil {
+		slog.Error("failed to list spam incident messages", slog.Int64("incident", incident.ID), slog.Any("err", err))
+		return false
+	}
+
+	restored := true
+	for _, message := range copies {
+		// Leave room for the header within the 2000 character limit
+		content := truncate(message.Content, 1900)
+		if _, err := b.Client.Rest.CreateMessage(snowflake.ID(message.ChannelID), discord.MessageCreate{
+			Content:         fmt.Sprintf("Restored message from <@%d>:\n%s", incident.AuthorID, content),
+			AllowedMentions: &discord.AllowedMentions{},
+		}); err != nil {
+			slog.Error("DisGo error(failed to restore message)", slog.Int64("message", message.MessageID), slog.Any("err", err))
+			restored = false
+		}
+	}
+	return restored
+}
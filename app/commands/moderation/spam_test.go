@@ -0,0 +1,153 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+
+	"clockey/app"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestContentHash(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"free nitro https://x.y", "free nitro https://x.y", true},
+		{"Free Nitro https://x.y", "free nitro https://X.Y", true},
+		{"free  nitro\nhttps://x.y ", " free nitro https://x.y", true},
+		{"free nitro https://x.y", "free nitro https://x.z", false},
+		{"freenitro", "free nitro", false},
+	}
+	for _, tc := range tests {
+		if same := contentHash(tc.a) == contentHash(tc.b); same != tc.same {
+			t.Errorf("contentHash(%q) == contentHash(%q) is %t, want %t", tc.a, tc.b, same, tc.same)
+		}
+	}
+}
+
+func TestSpamTracker(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	message := func(id int, channel int, content string, after time.Duration) trackedMessage {
+		return trackedMessage{
+			ID:        snowflake.ID(id),
+			ChannelID: snowflake.ID(channel),
+			Hash:      contentHash(content),
+			Content:   content,
+			CreatedAt: start.Add(after),
+		}
+	}
+
+	tests := map[string]struct {
+		cfg      app.SpamConfig
+		messages []trackedMessage
+		// flagged is the number of copies returned for the last message
+		flagged int
+	}{
+		"same content in enough channels": {
+			cfg: app.SpamConfig{Channels: 3, Window: 60},
+			messages: []trackedMessage{
+				message(1, 1, "spam", 0),
+				message(2, 2, "spam", time.Second),
+				message(3, 3, "Spam", 2*time.Second),
+			},
+			flagged: 3,
+		},
+		"same content in too few channels": {
+			cfg: app.SpamConfig{Channels: 3, Window: 60},
+			messages: []trackedMessage{
+				message(1, 1, "spam", 0),
+				message(2, 1, "spam", time.Second),
+				message(3, 2, "spam", 2*time.Second),
+			},
+		},
+		"only duplicates are flagged": {
+			cfg: app.SpamConfig{Channels: 2, Window: 60},
+			messages: []trackedMessage{
+				message(1, 1, "hello", 0),
+				message(2, 1, "spam", time.Second),
+				message(3, 2, "spam", 2*time.Second),
+			},
+			flagged: 2,
+		},
+		"copies outside the window": {
+			cfg: app.SpamConfig{Channels: 3, Window: 60},
+			messages: []trackedMessage{
+				message(1, 1, "spam", 0),
+				message(2, 2, "spam", time.Minute),
+				message(3, 3, "spam", 2*time.Minute),
+			},
+		},
+		"too many messages": {
+			cfg: app.SpamConfig{Messages: 3, Window: 10},
+			messages: []trackedMessage{
+				message(1, 1, "a", 0),
+				message(2, 1, "b", time.Second),
+				message(3, 1, "c", 2*time.Second),
+			},
+			flagged: 3,
+		},
+		"messages spread out": {
+			cfg: app.SpamConfig{Messages: 3, Window: 10},
+			messages: []trackedMessage{
+				message(1, 1, "a", 0),
+				message(2, 1, "b", 10*time.Second),
+				message(3, 1, "c", 20*time.Second),
+			},
+		},
+		"disabled": {
+			cfg: app.SpamConfig{Window: 60},
+			messages: []trackedMessage{
+				message(1, 1, "spam", 0),
+				message(2, 2, "spam", time.Second),
+				message(3, 3, "spam", 2*time.Second),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tracker := newSpamTracker()
+			var copies []trackedMessage
+			var reason string
+			for i, m := range tc.messages {
+				copies, reason = tracker.track(tc.cfg, 1, m, m.CreatedAt)
+				if i < len(tc.messages)-1 && len(copies) > 0 {
+					t.Fatalf("flagged early at message %d", i)
+				}
+			}
+			if len(copies) != tc.flagged {
+				t.Fatalf("flagged %d copies, want %d", len(copies), tc.flagged)
+			}
+			if tc.flagged > 0 && reason == "" {
+				t.Error("flagged without a reason")
+			}
+		})
+	}
+}
+
+func TestSpamTrackerTakesCopies(t *testing.T) {
+	cfg := app.SpamConfig{Channels: 2, Window: 60}
+	tracker := newSpamTracker()
+	now := time.Unix(1_700_000_000, 0)
+	spam := func(id int, channel int) trackedMessage {
+		return trackedMessage{ID: snowflake.ID(id), ChannelID: snowflake.ID(channel), Hash: contentHash("spam"), CreatedAt: now}
+	}
+
+	tracker.track(cfg, 1, spam(1, 1), now)
+	if copies, _ := tracker.track(cfg, 2, spam(2, 2), now); len(copies) != 0 {
+		t.Fatal("flagged copies of another author")
+	}
+	copies, _ := tracker.track(cfg, 1, spam(3, 2), now)
+	if len(copies) != 2 {
+		t.Fatalf("flagged %d copies, want 2", len(copies))
+	}
+	if copies, _ := tracker.track(cfg, 1, spam(4, 3), now); len(copies) != 0 {
+		t.Fatal("flagged copies that were already taken")
+	}
+
+	tracker.putBack(1, copies)
+	if copies, _ := tracker.track(cfg, 1, spam(5, 4), now); len(copies) != 4 {
+		t.Fatalf("flagged %d copies after putting them back, want 4", len(copies))
+	}
+}
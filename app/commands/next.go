@@ -2,21 +2,30 @@ package commands
 
 import (
 	"cmp"
+	"context"
 	"fmt"
+	"log/slog"
 	"slices"
-	"strings"
+	"sync"
+	"time"
+
+	"clockey/app"
+	"clockey/app/liquipedia"
+	"clockey/database/sqlc"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 var Next = discord.SlashCommandCreate{
 	Name:        "next",
-	Description: "Next game for OG",
+	Description: "Next games for OG",
 	Options: []discord.ApplicationCommandOption{
 		discord.ApplicationCommandOptionString{
 			Name:        "game",
-			Description: "Which game do you want to know",
+			Description: "Only list matches of this game",
 			Choices: []discord.ApplicationCommandOptionChoiceString{
 				{
 					Name:  "Dota",
@@ -35,35 +44,206 @@ var Next = discord.SlashCommandCreate{
 					Value: "HoK",
 				},
 			},
-			Required: true,
+			Required: false,
+		},
+		// A match takes up to 4 of the 40 components a message can hold
+		discord.ApplicationCommandOptionInt{
+			Name:        "count",
+			Description: "How many matches to list, 5 by default",
+			MinValue:    omit.Ptr(1),
+			MaxValue:    omit.Ptr(9),
+			Required:    false,
 		},
 	},
 }
 
-func NextCommandHandler() handler.SlashCommandHandler {
+// nextMatch is an upcoming match from the match feed, the events table or
+// both when they describe the same match.
+type nextMatch struct {
+	Game       string
+	Name       string
+	Tournament string
+	Time       int64
+	Stream     string
+	Event      *sqlc.Event
+}
+
+func NextCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
-		game := data.String("game")
-		eventList, err := e.Client().Rest.GetGuildScheduledEvents(*e.GuildID(), false)
+		game, filtered := data.OptString("game")
+		count, ok := data.OptInt("count")
+		if !ok {
+			count = 5
+		}
+
+		if err := e.DeferCreateMessage(false); err != nil {
+			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events, err := b.DB.Queries.ListUpcomingEvents(ctx, time.Now().Unix())
 		if err != nil {
+			slog.Error("failed to list upcoming events", slog.Any("err", err))
+			if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+				Content: omit.Ptr("Error getting upcoming games, please try again"),
+			}); err != nil {
+				slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			}
 			return err
 		}
 
-		sortedEvents := slices.SortedStableFunc(slices.Values(eventList), func(a, b discord.GuildScheduledEvent) int {
-			return cmp.Compare(a.ScheduledStartTime.Unix(), b.ScheduledStartTime.Unix())
+		// Uncached feeds wait on each other for the request interval, the
+		// deferred response leaves plenty of time for that
+		feedCtx, feedCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer feedCancel()
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			matches []nextMatch
+		)
+		for name, wiki := range liquipedia.Games {
+			if filtered && name != game {
+				continue
+			}
+			wg.Go(func() {
+				feed := feedMatches(feedCtx, b, name, wiki)
+				mu.Lock()
+				defer mu.Unlock()
+				matches = append(matches, feed...)
+			})
+		}
+		wg.Wait()
+		matches = withEvents(matches, events)
+		if filtered {
+			matches = slices.DeleteFunc(matches, func(match nextMatch) bool {
+				return match.Game != game
+			})
+		}
+		slices.SortStableFunc(matches, func(a, b nextMatch) int {
+			return cmp.Compare(a.Time, b.Time)
 		})
 
-		result := slices.IndexFunc(sortedEvents, func(event discord.GuildScheduledEvent) bool {
-			return strings.Contains(event.Name, game)
+		if len(matches) == 0 {
+			content := "No upcoming games found"
+			if filtered {
+				content += " for " + game
+			}
+			if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+				Content: omit.Ptr(content),
+			}); err != nil {
+				slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+				return err
+			}
+			return nil
+		}
+
+		title := "# Next OG games"
+		if filtered {
+			title = "# Next OG " + game + " games"
+		}
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Components: omit.Ptr(nextLayout(title, *e.GuildID(), matches[:min(len(matches), count)])),
+			Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
+		}); err != nil {
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+}
+
+// feedMatches lists the upcoming matches of a game from Liquipedia. The
+// events table still covers the game if the feed is unavailable.
+func feedMatches(ctx context.Context, b *app.Bot, game string, wiki string) []nextMatch {
+	results, err := b.Liquipedia.UpcomingMatches(ctx, wiki)
+	if err != nil {
+		slog.Error("failed to get upcoming matches", slog.String("wiki", wiki), slog.Any("err", err))
+		return nil
+	}
+
+	var matches []nextMatch
+	for _, result := range results {
+		us, them, ok := result.Sides(b.Liquipedia.Team(wiki))
+		if !ok || result.Date.Before(time.Now()) {
+			continue
+		}
+		matches = append(matches, nextMatch{
+			Game:       game,
+			Name:       us.Name + " vs " + cmp.Or(them.Name, "TBD"),
+			Tournament: result.Tournament,
+			Time:       result.Date.Unix(),
+			Stream:     result.Stream.URL(),
 		})
+	}
+	return matches
+}
 
-		if result != -1 {
-			return e.CreateMessage(discord.MessageCreate{
-				Content: fmt.Sprintf("https://discord.com/events/%s/%s", e.GuildID(), sortedEvents[result].ID),
-			})
-		} else {
-			return e.CreateMessage(discord.MessageCreate{
-				Content: "No upcoming game found for " + game,
+// withEvents links every match to the event of the same game starting within
+// an hour of it. Game events missing from the feed are listed on their own.
+func withEvents(matches []nextMatch, events []sqlc.Event) []nextMatch {
+	for _, event := range events {
+		if _, ok := liquipedia.Games[string(event.Type)]; !ok {
+			continue
+		}
+		i := slices.IndexFunc(matches, func(match nextMatch) bool {
+			return match.Event == nil && match.Game == string(event.Type) && abs(match.Time-event.Time) <= 3600
+		})
+		if i == -1 {
+			matches = append(matches, nextMatch{
+				Game:  string(event.Type),
+				Name:  event.Name,
+				Time:  event.Time,
+				Event: &event,
 			})
+			continue
 		}
+		matches[i].Event = &event
+	}
+	return matches
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func nextLayout(title string, guildID snowflake.ID, matches []nextMatch) []discord.LayoutComponent {
+	var components []discord.ContainerSubComponent
+	for i, match := range matches {
+		if i > 0 {
+			components = append(components, discord.SeparatorComponent{})
+		}
+
+		content := fmt.Sprintf("**%s** - %s\n", match.Game, match.Name)
+		if match.Tournament != "" {
+			content += match.Tournament + "\n"
+		}
+		content += fmt.Sprintf("<t:%d:F> (<t:%d:R>)", match.Time, match.Time)
+		if match.Stream != "" {
+			content += fmt.Sprintf("\n[Watch live](%s)", match.Stream)
+		}
+
+		text := discord.TextDisplayComponent{Content: content}
+		if match.Event == nil || !match.Event.ScheduledEventID.Valid {
+			components = append(components, text)
+			continue
+		}
+		components = append(components, discord.SectionComponent{
+			Components: []discord.SectionSubComponent{text},
+			Accessory:  discord.NewLinkButton("Event", fmt.Sprintf("https://discord.com/events/%s/%d", guildID, match.Event.ScheduledEventID.Int64)),
+		})
+	}
+
+	return []discord.LayoutComponent{
+		discord.TextDisplayComponent{
+			Content: title,
+		},
+		discord.ContainerComponent{
+			Components: components,
+		},
 	}
 }
@@ -1,95 +0,0 @@
-package predictions
-
-import (
-	"context"
-	"fmt"
-	"log/slog"
-	"slices"
-	"time"
-
-	"clockey/app"
-	"clockey/database/sqlc"
-
-	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/handler"
-	"github.com/disgoorg/omit"
-)
-
-var Add = discord.SlashCommandCreate{
-	Name:        "add",
-	Description: "Add a prediction score to the chosen scoreboard",
-	Options: []discord.ApplicationCommandOption{
-		discord.ApplicationCommandOptionString{
-			Name:        "game",
-			Description: "The game leaderboard to update",
-			Required:    true,
-			Choices: []discord.ApplicationCommandOptionChoiceString{
-				{
-					Name:  "Dota",
-					Value: "Dota",
-				},
-				{
-					Name:  "CS",
-					Value: "CS",
-				},
-				{
-					Name:  "MLBB",
-					Value: "MLBB",
-				},
-				{
-					Name:  "HoK",
-					Value: "HoK",
-				},
-			},
-		},
-		discord.ApplicationCommandOptionRole{
-			Name:        "role",
-			Description: "The role to add score to",
-		},
-	},
-}
-
-func AddCommandHandler(b *app.Bot) handler.SlashCommandHandler {
-	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
-		if err := e.DeferCreateMessage(false); err != nil {
-			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
-			return err
-		}
-
-		guildMembers := e.Client().Caches.Members(*e.GuildID())
-
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
-		tx, err := b.DB.Conn.Begin(ctx)
-		if err != nil {
-			return err
-		}
-
-		defer func() {
-			if err := tx.Rollback(ctx); err != nil {
-				slog.Error("failed to rollback transaction", slog.Any("err", err))
-			}
-		}()
-
-		count := 0
-		for member := range guildMembers {
-			if slices.Contains(member.RoleIDs, data.Role("role").ID) {
-				if err := b.DB.Queries.WithTx(tx).UpdateScoreboardForGame(ctx, sqlc.UpdateScoreboardForGameParams{
-					Member: int64(member.User.ID),
-					Game:   sqlc.ScoreboardGame(data.String("game")),
-				}); err != nil {
-					slog.Error("failed to update scoreboard", slog.Any("member", member.User.ID), slog.Any("err", err))
-					return err
-				}
-				count++
-			}
-		}
-		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-			Content: omit.Ptr(fmt.Sprintf("Added score for %d members to the %s scoreboard", count, data.String("game"))),
-		}); err != nil {
-			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-			return err
-		}
-		return tx.Commit(ctx)
-	}
-}
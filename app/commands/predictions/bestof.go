@@ -1,24 +1,54 @@
 package predictions
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/app/liquipedia"
+	"clockey/database/sqlc"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/omit"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var BestOf = discord.SlashCommandCreate{
-	Name:        "bo",
-	Description: "Create prediction roles for selected game",
+	Name:                     "bo",
+	Description:              "Post a prediction for the selected game",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
 	Options: []discord.ApplicationCommandOption{
 		discord.ApplicationCommandOptionString{
-			Name:         "game",
-			Description:  "The game to create prediction roles for",
-			Required:     true,
-			Autocomplete: true,
+			Name:        "game",
+			Description: "The game the prediction is scored on",
+			Required:    true,
+			Choices: []discord.ApplicationCommandOptionChoiceString{
+				{
+					Name:  "Dota",
+					Value: "Dota",
+				},
+				{
+					Name:  "CS",
+					Value: "CS",
+				},
+				{
+					Name:  "MLBB",
+					Value: "MLBB",
+				},
+				{
+					Name:  "HoK",
+					Value: "HoK",
+				},
+			},
 		},
 		discord.ApplicationCommandOptionInt{
 			Name:        "series_length",
@@ -37,6 +67,10 @@ var BestOf = discord.SlashCommandCreate{
 					Name:  "Bo3",
 					Value: 3,
 				},
+				{
+					Name:  "Bo4",
+					Value: 4,
+				},
 				{
 					Name:  "Bo5",
 					Value: 5,
@@ -45,297 +79,293 @@ var BestOf = discord.SlashCommandCreate{
 					Name:  "Bo7",
 					Value: 7,
 				},
+				{
+					Name:  "Bo9",
+					Value: 9,
+				},
 			},
 		},
+		discord.ApplicationCommandOptionString{
+			Name:        "deadline",
+			Description: "The unix time predictions lock at",
+			Required:    true,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "opponent",
+			Description: "The team OG is playing, e.g. Liquid",
+			Required:    false,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "name",
+			Description: "The match being predicted, e.g. OG vs Liquid",
+			Required:    false,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:         "match",
+			Description:  "The Liquipedia match, to score the prediction once it finishes",
+			Required:     false,
+			Autocomplete: true,
+		},
 	},
 }
 
-func BestOfCommandHandler() handler.SlashCommandHandler {
+func BestOfCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		game := data.String("game")
+		seriesLength := data.Int("series_length")
+		opponent, hasOpponent := data.OptString("opponent")
+
+		deadline, err := strconv.ParseInt(data.String("deadline"), 10, 64)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: data.String("deadline") + " is not a valid unix time. Please try again.",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+		if time.Unix(deadline, 0).Before(time.Now()) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "The deadline has already passed. Please try again.",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		var wiki, liquipediaMatch pgtype.Text
+		if matchID, provided := data.OptString("match"); provided {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			them, err := upcomingOpponent(ctx, b, liquipedia.Games[game], matchID)
+			if err != nil {
+				slog.Error("failed to find liquipedia match", slog.String("match", matchID), slog.Any("err", err))
+				return e.CreateMessage(discord.MessageCreate{
+					Content: "Couldn't find that match on Liquipedia, pick one from the list or leave it empty",
+					Flags:   discord.MessageFlagEphemeral,
+				})
+			}
+			wiki = pgtype.Text{String: liquipedia.Games[game], Valid: true}
+			liquipediaMatch = pgtype.Text{String: matchID, Valid: true}
+			if !hasOpponent {
+				opponent, hasOpponent = cmp.Or(them.Teamtemplate.Shortname, them.Name), true
+			}
+		}
+
+		name := data.String("name")
+		if name == "" && hasOpponent {
+			name = "OG vs " + opponent
+		} else if name == "" {
+			name = game
+		}
+
 		if err := e.DeferCreateMessage(false); err != nil {
 			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
 			return err
 		}
 
-		game := data.String("game")
-		seriesLength := data.Int("series_length")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		match, err := b.DB.Queries.CreatePredictionMatch(ctx, sqlc.CreatePredictionMatchParams{
+			Name:            name,
+			Game:            sqlc.ScoreboardGame(game),
+			SeriesLength:    int16(seriesLength),
+			Deadline:        deadline,
+			Opponent:        pgtype.Text{String: opponent, Valid: hasOpponent},
+			Wiki:            wiki,
+			LiquipediaMatch: liquipediaMatch,
+		})
+		if err != nil {
+			slog.Error("failed to create prediction match", slog.Any("err", err))
+			return err
+		}
 
-		switch seriesLength {
-		case 1:
-			rolesToBeCreated := []string{
-				game + "1-0",
-				game + "0-1",
-			}
-			for _, roleName := range rolesToBeCreated {
-				if _, err := e.Client().Rest.CreateRole(*e.GuildID(), discord.RoleCreate{Name: roleName}); err != nil {
-					slog.Error("DisGo error(failed to create role)", slog.String("role", roleName), slog.Any("err", err))
-					return err
-				}
-			}
-		case 2:
-			rolesToBeCreated := []string{
-				game + "2-0",
-				game + "1-1",
-				game + "0-2",
-			}
-			for _, roleName := range rolesToBeCreated {
-				if _, err := e.Client().Rest.CreateRole(*e.GuildID(), discord.RoleCreate{Name: roleName}); err != nil {
-					slog.Error("DisGo error(failed to create role)", slog.String("role", roleName), slog.Any("err", err))
-					return err
-				}
-			}
-		case 3:
-			rolesToBeCreated := []string{
-				game + "2-0",
-				game + "2-1",
-				game + "1-2",
-				game + "0-2",
-			}
-			for _, roleName := range rolesToBeCreated {
-				if _, err := e.Client().Rest.CreateRole(*e.GuildID(), discord.RoleCreate{Name: roleName}); err != nil {
-					slog.Error("DisGo error(failed to create role)", slog.String("role", roleName), slog.Any("err", err))
-					return err
-				}
-			}
-		case 5:
-			rolesToBeCreated := []string{
-				game + "3-0",
-				game + "3-1",
-				game + "3-2",
-				game + "2-3",
-				game + "1-3",
-				game + "0-3",
-			}
-			for _, roleName := range rolesToBeCreated {
-				if _, err := e.Client().Rest.CreateRole(*e.GuildID(), discord.RoleCreate{Name: roleName}); err != nil {
-					slog.Error("DisGo error(failed to create role)", slog.String("role", roleName), slog.Any("err", err))
-					return err
-				}
-			}
-		case 7:
-			rolesToBeCreated := []string{
-				game + "4-0",
-				game + "4-1",
-				game + "4-2",
-				game + "4-3",
-				game + "3-4",
-				game + "2-4",
-				game + "1-4",
-				game + "0-4",
-			}
-			for _, roleName := range rolesToBeCreated {
-				if _, err := e.Client().Rest.CreateRole(*e.GuildID(), discord.RoleCreate{Name: roleName}); err != nil {
-					slog.Error("DisGo error(failed to create role)", slog.String("role", roleName), slog.Any("err", err))
-					return err
-				}
-			}
+		msg, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content:    omit.Ptr(predictionContent(match)),
+			Components: omit.Ptr(predictionButtons(match)),
+		})
+		if err != nil {
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			return err
 		}
 
-		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-			Content: omit.Ptr("Prediction roles created for " + game + " best of " + fmt.Sprint(seriesLength)),
+		if err := b.DB.Queries.SetPredictionMatchMessage(ctx, sqlc.SetPredictionMatchMessageParams{
+			ID:        match.ID,
+			MessageID: pgtype.Int8{Int64: int64(msg.ID), Valid: true},
+			ChannelID: pgtype.Int8{Int64: int64(msg.ChannelID), Valid: true},
 		}); err != nil {
-			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			slog.Error("failed to set prediction match message", slog.Int64("match", match.ID), slog.Any("err", err))
 			return err
 		}
 		return nil
 	}
 }
 
-var DeleteBestOf = discord.SlashCommandCreate{
-	Name:        "deletebo",
-	Description: "Delete all prediction roles for selected game",
-	Options: []discord.ApplicationCommandOption{
-		discord.ApplicationCommandOptionString{
-			Name:         "game",
-			Description:  "The game to delete prediction roles for",
-			Required:     true,
-			Autocomplete: true,
-		},
-		discord.ApplicationCommandOptionInt{
-			Name:        "series_length",
-			Description: "The length of the series",
-			Required:    true,
-			Choices: []discord.ApplicationCommandOptionChoiceInt{
-				{
-					Name:  "Bo1",
-					Value: 1,
-				},
-				{
-					Name:  "Bo2",
-					Value: 2,
-				},
-				{
-					Name:  "Bo3",
-					Value: 3,
-				},
-				{
-					Name:  "Bo5",
-					Value: 5,
-				},
-				{
-					Name:  "Bo7",
-					Value: 7,
-				},
-			},
-		},
-	},
+// upcomingOpponent returns the team OG plays in the upcoming Liquipedia match.
+func upcomingOpponent(ctx context.Context, b *app.Bot, wiki string, matchID string) (liquipedia.Match2Opponent, error) {
+	matches, err := b.Liquipedia.UpcomingMatches(ctx, wiki)
+	if err != nil {
+		return liquipedia.Match2Opponent{}, err
+	}
+	for _, match := range matches {
+		if match.Match2ID != matchID {
+			continue
+		}
+		if _, them, ok := match.Sides(b.Liquipedia.Team(wiki)); ok {
+			return them, nil
+		}
+	}
+	return liquipedia.Match2Opponent{}, fmt.Errorf("match %s not found on %s", matchID, wiki)
 }
 
-func DeleteBestOfCommandHandler() handler.SlashCommandHandler {
-	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
-		if err := e.DeferCreateMessage(false); err != nil {
-			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
-			return err
+// BestOfMatchAutocompleteHandler suggests the upcoming Liquipedia matches of
+// the chosen game.
+func BestOfMatchAutocompleteHandler(b *app.Bot) handler.AutocompleteHandler {
+	return func(e *handler.AutocompleteEvent) error {
+		choices := []discord.AutocompleteChoice{}
+		wiki, ok := liquipedia.Games[e.Data.String("game")]
+		if !ok {
+			return e.AutocompleteResult(choices)
 		}
 
-		game := data.String("game")
-		seriesLength := data.Int("series_length")
-		switch seriesLength {
-		case 1:
-			rolesToBeDeleted := []string{
-				game + "1-0",
-				game + "0-1",
-			}
-			if roles, err := e.Client().Rest.GetRoles(*e.GuildID()); err == nil {
-				for _, role := range roles {
-					if slices.Contains(rolesToBeDeleted, role.Name) {
-						if err := e.Client().Rest.DeleteRole(*e.GuildID(), role.ID); err != nil {
-							slog.Error("DisGo error(failed to delete role)", slog.String("role", role.Name), slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			} else {
-				slog.Error("DisGo error(failed to get roles)", slog.Any("err", err))
-				return err
-			}
-		case 2:
-			rolesToBeDeleted := []string{
-				game + "2-0",
-				game + "1-1",
-				game + "0-2",
-			}
-			if roles, err := e.Client().Rest.GetRoles(*e.GuildID()); err == nil {
-				for _, role := range roles {
-					if slices.Contains(rolesToBeDeleted, role.Name) {
-						if err := e.Client().Rest.DeleteRole(*e.GuildID(), role.ID); err != nil {
-							slog.Error("DisGo error(failed to delete role)", slog.String("role", role.Name), slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			} else {
-				slog.Error("DisGo error(failed to get roles)", slog.Any("err", err))
-				return err
-			}
-		case 3:
-			rolesToBeDeleted := []string{
-				game + "2-0",
-				game + "2-1",
-				game + "1-2",
-				game + "0-2",
-			}
-			if roles, err := e.Client().Rest.GetRoles(*e.GuildID()); err == nil {
-				for _, role := range roles {
-					if slices.Contains(rolesToBeDeleted, role.Name) {
-						if err := e.Client().Rest.DeleteRole(*e.GuildID(), role.ID); err != nil {
-							slog.Error("DisGo error(failed to delete role)", slog.String("role", role.Name), slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			} else {
-				slog.Error("DisGo error(failed to get roles)", slog.Any("err", err))
-				return err
-			}
-		case 5:
-			rolesToBeDeleted := []string{
-				game + "3-0",
-				game + "3-1",
-				game + "3-2",
-				game + "2-3",
-				game + "1-3",
-				game + "0-3",
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		matches, err := b.Liquipedia.UpcomingMatches(ctx, wiki)
+		if err != nil {
+			slog.Error("failed to get upcoming matches", slog.String("wiki", wiki), slog.Any("err", err))
+			return e.AutocompleteResult(choices)
+		}
+
+		query := strings.ToLower(e.Data.Focused().String())
+		for _, match := range matches {
+			if len(choices) == 25 {
+				break
 			}
-			if roles, err := e.Client().Rest.GetRoles(*e.GuildID()); err == nil {
-				for _, role := range roles {
-					if slices.Contains(rolesToBeDeleted, role.Name) {
-						if err := e.Client().Rest.DeleteRole(*e.GuildID(), role.ID); err != nil {
-							slog.Error("DisGo error(failed to delete role)", slog.String("role", role.Name), slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			} else {
-				slog.Error("DisGo error(failed to get roles)", slog.Any("err", err))
-				return err
+			us, them, ok := match.Sides(b.Liquipedia.Team(wiki))
+			if !ok {
+				continue
 			}
-		case 7:
-			rolesToBeDeleted := []string{
-				game + "4-0",
-				game + "4-1",
-				game + "4-2",
-				game + "4-3",
-				game + "3-4",
-				game + "2-4",
-				game + "1-4",
-				game + "0-4",
+			name := fmt.Sprintf("%s vs %s - %s (%s UTC)", us.Name, them.Name, match.Tournament, match.Date.Format("Jan 2 15:04"))
+			if runes := []rune(name); len(runes) > 100 {
+				name = string(runes[:97]) + "..."
 			}
-			if roles, err := e.Client().Rest.GetRoles(*e.GuildID()); err == nil {
-				for _, role := range roles {
-					if slices.Contains(rolesToBeDeleted, role.Name) {
-						if err := e.Client().Rest.DeleteRole(*e.GuildID(), role.ID); err != nil {
-							slog.Error("DisGo error(failed to delete role)", slog.String("role", role.Name), slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			} else {
-				slog.Error("DisGo error(failed to get roles)", slog.Any("err", err))
-				return err
+			if strings.Contains(strings.ToLower(name), query) {
+				choices = append(choices, discord.AutocompleteChoiceString{
+					Name:  name,
+					Value: match.Match2ID,
+				})
 			}
 		}
-		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-			Content: omit.Ptr("Prediction roles deleted for " + game + " best of " + fmt.Sprint(seriesLength)),
+		return e.AutocompleteResult(choices)
+	}
+}
+
+// PredictComponentHandler records a member's pick from the prediction
+// message. Picks can be changed until the deadline.
+func PredictComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		matchID, err := strconv.ParseInt(e.Vars["match"], 10, 64)
+		if err != nil {
+			return err
+		}
+		scoreline := e.Vars["scoreline"]
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		match, err := b.DB.Queries.GetPredictionMatch(ctx, matchID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This prediction no longer exists",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get prediction match", slog.Int64("match", matchID), slog.Any("err", err))
+			return err
+		}
+
+		if match.Result.Valid || time.Now().Unix() >= match.Deadline {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Predictions for this match are locked",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+		if !slices.Contains(scorelines(int(match.SeriesLength)), scoreline) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: scoreline + " is not a possible score for this match",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		if err := b.DB.Queries.SetPrediction(ctx, sqlc.SetPredictionParams{
+			Match:     match.ID,
+			Member:    int64(e.User().ID),
+			Scoreline: scoreline,
 		}); err != nil {
-			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			slog.Error("failed to set prediction", slog.Int64("match", match.ID), slog.Any("member", e.User().ID), slog.Any("err", err))
 			return err
 		}
-		return nil
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content: "Your prediction of " + scorelineLabel(scoreline, match.Opponent.String) + " for " + match.Name + " has been saved",
+			Flags:   discord.MessageFlagEphemeral,
+		})
 	}
 }
 
-func BestOfAutocompleteHandler() handler.AutocompleteHandler {
-	return func(e *handler.AutocompleteEvent) error {
-		if len(e.Data.Focused().String()) > 0 {
-			return e.AutocompleteResult([]discord.AutocompleteChoice{
-				discord.AutocompleteChoiceString{
-					Name:  "Dota",
-					Value: "Dota",
-				},
-				discord.AutocompleteChoiceString{
-					Name:  "CS",
-					Value: "CS",
-				},
-				discord.AutocompleteChoiceString{
-					Name:  "MLBB",
-					Value: "MLBB",
-				},
-				discord.AutocompleteChoiceString{
-					Name:  "HoK",
-					Value: "HoK",
-				},
-				discord.AutocompleteChoiceString{
-					Name:  "Extra",
-					Value: "EX",
-				},
-				discord.AutocompleteChoiceString{
-					Name:  e.Data.Focused().String(),
-					Value: e.Data.Focused().String(),
-				},
+func predictionContent(match sqlc.PredictionMatch) string {
+	content := fmt.Sprintf("# %s prediction\n%s (Bo%d)\n", match.Game, match.Name, match.SeriesLength)
+	if match.Result.Valid {
+		return content + "Result: " + match.Result.String
+	}
+	return content + fmt.Sprintf("Predictions lock <t:%d:R>", match.Deadline)
+}
+
+// predictionButtons builds one button per scoreline, five to a row. Once the
+// match is resolved the buttons are disabled and the result is highlighted.
+func predictionButtons(match sqlc.PredictionMatch) []discord.LayoutComponent {
+	var rows []discord.LayoutComponent
+	for chunk := range slices.Chunk(scorelines(int(match.SeriesLength)), 5) {
+		row := discord.ActionRowComponent{}
+		for _, scoreline := range chunk {
+			style := discord.ButtonStyleSecondary
+			if match.Result.Valid && match.Result.String == scoreline {
+				style = discord.ButtonStyleSuccess
+			}
+			row.Components = append(row.Components, discord.ButtonComponent{
+				Label:    scorelineLabel(scoreline, match.Opponent.String),
+				Style:    style,
+				CustomID: fmt.Sprintf("/predict/%d/%s", match.ID, scoreline),
+				Disabled: match.Result.Valid,
 			})
-		} else {
-			return e.AutocompleteResult([]discord.AutocompleteChoice{})
 		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// scorelines lists the possible results of a series from OG's point of
+// view, best result first. Even lengths can also end in a draw.
+func scorelines(seriesLength int) []string {
+	if seriesLength < 1 {
+		return nil
+	}
+
+	wins := seriesLength/2 + 1
+	var results []string
+	for lost := 0; lost < wins && wins+lost <= seriesLength; lost++ {
+		results = append(results, fmt.Sprintf("%d-%d", wins, lost))
+	}
+	if seriesLength%2 == 0 {
+		results = append(results, fmt.Sprintf("%d-%d", seriesLength/2, seriesLength/2))
+	}
+	for won := wins - 1; won >= 0; won-- {
+		if wins+won <= seriesLength {
+			results = append(results, fmt.Sprintf("%d-%d", won, wins))
+		}
+	}
+	return results
+}
+
+// scorelineLabel reads "OG 2-1 Liquid" when the opponent is known.
+func scorelineLabel(scoreline string, opponent string) string {
+	if opponent == "" {
+		return scoreline
 	}
+	return "OG " + scoreline + " " + opponent
 }
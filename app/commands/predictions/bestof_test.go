@@ -0,0 +1,44 @@
+package predictions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScorelines(t *testing.T) {
+	tests := []struct {
+		seriesLength int
+		want         []string
+	}{
+		{0, nil},
+		{1, []string{"1-0", "0-1"}},
+		{2, []string{"2-0", "1-1", "0-2"}},
+		{3, []string{"2-0", "2-1", "1-2", "0-2"}},
+		{4, []string{"3-0", "3-1", "2-2", "1-3", "0-3"}},
+		{5, []string{"3-0", "3-1", "3-2", "2-3", "1-3", "0-3"}},
+		{7, []string{"4-0", "4-1", "4-2", "4-3", "3-4", "2-4", "1-4", "0-4"}},
+		{9, []string{"5-0", "5-1", "5-2", "5-3", "5-4", "4-5", "3-5", "2-5", "1-5", "0-5"}},
+	}
+	for _, tc := range tests {
+		if got := scorelines(tc.seriesLength); !slices.Equal(got, tc.want) {
+			t.Errorf("scorelines(%d) = %v, want %v", tc.seriesLength, got, tc.want)
+		}
+	}
+}
+
+func TestScorelineLabel(t *testing.T) {
+	tests := []struct {
+		scoreline string
+		opponent  string
+		want      string
+	}{
+		{"2-1", "Team Liquid", "OG 2-1 Team Liquid"},
+		{"0-2", "Team Liquid", "OG 0-2 Team Liquid"},
+		{"1-1", "", "1-1"},
+	}
+	for _, tc := range tests {
+		if got := scorelineLabel(tc.scoreline, tc.opponent); got != tc.want {
+			t.Errorf("scorelineLabel(%q, %q) = %q, want %q", tc.scoreline, tc.opponent, got, tc.want)
+		}
+	}
+}
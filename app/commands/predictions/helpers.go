@@ -1,11 +0,0 @@
-package predictions
-
-import "github.com/disgoorg/snowflake/v2"
-
-var (
-	theOracleRoleID  = snowflake.ID(1379019909971054594)
-	dotaOracleRoleID = snowflake.ID(729106634437296148)
-	csOracleRoleID   = snowflake.ID(729106753085636688)
-	mlbbOracleRoleID = snowflake.ID(1378962478263832636)
-	hokOracleRoleID  = snowflake.ID(1378962836784414720)
-)
@@ -2,42 +2,53 @@ package predictions
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"clockey/app"
 
-	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/omit"
+	"github.com/jackc/pgx/v5"
 )
 
 var Reset = discord.SlashCommandCreate{
-	Name:        "reset",
-	Description: "Reset the monthly prediction leaderboard",
+	Name:                     "reset",
+	Description:              "Close the current prediction season and start a new one",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
 }
 
 func ResetCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		season, err := b.DB.Queries.GetCurrentSeason(ctx)
+		if err != nil {
+			slog.Error("failed to get current season", slog.Any("err", err))
+			return err
+		}
+
 		buttons := discord.ActionRowComponent{
 			Components: []discord.InteractiveComponent{
 				discord.ButtonComponent{
 					Label:    "Yes",
 					Style:    discord.ButtonStyleDanger,
-					CustomID: "reset_leaderboard_yes",
+					CustomID: fmt.Sprintf("/reset/%d/yes", season.ID),
 				},
 				discord.ButtonComponent{
 					Label:    "No",
 					Style:    discord.ButtonStyleSecondary,
-					CustomID: "reset_leaderboard_no",
+					CustomID: fmt.Sprintf("/reset/%d/no", season.ID),
 				},
 			},
 		}
 
 		if err := e.CreateMessage(discord.MessageCreate{
-			Content: "Are you sure you want to reset the monthly prediction leaderboard?",
+			Content: fmt.Sprintf("Are you sure you want to close the %s prediction season and start a new one?", season.Name),
 			Components: []discord.LayoutComponent{
 				buttons,
 			},
@@ -46,45 +57,69 @@ func ResetCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			return err
 		}
 
-		go func() {
-			ch, cls := bot.NewEventCollector(e.Client(),
-				func(c *events.ComponentInteractionCreate) bool {
-					return c.Data.CustomID() == "reset_leaderboard_yes" || c.Data.CustomID() == "reset_leaderboard_no"
-				})
-			defer cls()
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-			select {
-			case <-ctx.Done():
-				return
-			case c := <-ch:
-				if c.Data.CustomID() == "reset_leaderboard_yes" {
-					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-					defer cancel()
-					if err := b.DB.Queries.ClearScoreboard(ctx); err != nil {
-						if err := c.UpdateMessage(discord.MessageUpdate{
-							Content: omit.Ptr("Failed to reset scoreboard, please try again"),
-						}); err != nil {
-							slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-						}
-					}
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Content: omit.Ptr("Monthly prediction leaderboard reset successfully"),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-					}
-				} else if c.Data.CustomID() == "reset_leaderboard_no" {
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Content: omit.Ptr("Monthly prediction leaderboard reset cancelled"),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-					}
-					return
-				} else {
-					return
-				}
+		return nil
+	}
+}
+
+// ResetComponentHandler confirms /reset. The season is in the custom ID so an
+// old confirmation can't close the season that replaced it.
+func ResetComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		content := "Prediction season reset cancelled"
+		if e.Vars["action"] == "yes" {
+			seasonID, err := strconv.ParseInt(e.Vars["season"], 10, 64)
+			if err != nil {
+				return err
 			}
-		}()
+			content, err = resetSeason(b, seasonID)
+			if err != nil {
+				slog.Error("failed to reset season", slog.Int64("season", seasonID), slog.Any("err", err))
+				content = "Failed to reset season, please try again"
+			}
+		}
+
+		if err := e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr(content),
+			Components: &[]discord.LayoutComponent{},
+		}); err != nil {
+			slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
+			return err
+		}
 		return nil
 	}
 }
+
+// resetSeason closes the season and opens the next one, named after the
+// month it starts in.
+func resetSeason(b *app.Bot, seasonID int64) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	closed, err := b.DB.Queries.WithTx(tx).CloseCurrentSeason(ctx)
+	if err != nil {
+		return "", err
+	}
+	if closed.ID != seasonID {
+		return "This season has already been closed", nil
+	}
+
+	opened, err := b.DB.Queries.WithTx(tx).CreateSeason(ctx, time.Now().Format("January 2006"))
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s prediction season closed, %s has started", closed.Name, opened.Name), nil
+}
@@ -0,0 +1,236 @@
+package predictions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var Resolve = discord.SlashCommandCreate{
+	Name:                     "resolve",
+	Description:              "Set the final score of a prediction and award points",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionString{
+			Name:         "match",
+			Description:  "The prediction to resolve",
+			Required:     true,
+			Autocomplete: true,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:         "score",
+			Description:  "The final score of the series",
+			Required:     true,
+			Autocomplete: true,
+		},
+	},
+}
+
+func ResolveCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		matchID, err := strconv.ParseInt(data.String("match"), 10, 64)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Please pick a prediction from the list",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		match, err := b.DB.Queries.GetPredictionMatch(ctx, matchID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Please pick a prediction from the list",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get prediction match", slog.Int64("match", matchID), slog.Any("err", err))
+			return err
+		}
+
+		score := data.String("score")
+		if !slices.Contains(scorelines(int(match.SeriesLength)), score) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: score + " is not a possible score for this match",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		if err := e.DeferCreateMessage(false); err != nil {
+			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
+			return err
+		}
+
+		replyText, _, err := resolveMatch(b, match, score)
+		if err != nil {
+			slog.Error("failed to resolve prediction match", slog.Int64("match", match.ID), slog.Any("err", err))
+			replyText = "Failed to resolve prediction, please try again"
+		}
+
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content:         omit.Ptr(replyText),
+			AllowedMentions: &discord.AllowedMentions{},
+		}); err != nil {
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			return err
+		}
+
+		refreshPredictionMessage(b, e.Client(), matchID)
+		return nil
+	}
+}
+
+// refreshPredictionMessage renders the prediction message from the stored
+// match, so it shows the result that was actually saved.
+func refreshPredictionMessage(b *app.Bot, client *bot.Client, matchID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	match, err := b.DB.Queries.GetPredictionMatch(ctx, matchID)
+	if err != nil {
+		slog.Error("failed to get prediction match", slog.Int64("match", matchID), slog.Any("err", err))
+		return
+	}
+	if !match.MessageID.Valid {
+		return
+	}
+	if _, err := client.Rest.UpdateMessage(snowflake.ID(match.ChannelID.Int64), snowflake.ID(match.MessageID.Int64), discord.MessageUpdate{
+		Content:    omit.Ptr(predictionContent(match)),
+		Components: omit.Ptr(predictionButtons(match)),
+	}); err != nil {
+		slog.Error("DisGo error(failed to update prediction message)", slog.Any("err", err))
+	}
+}
+
+// resolveMatch stores the result and awards a point to every member who
+// predicted it. resolved is false when the match already had a result.
+func resolveMatch(b *app.Bot, match sqlc.PredictionMatch, score string) (summary string, resolved bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	rows, err := b.DB.Queries.WithTx(tx).ResolvePredictionMatch(ctx, sqlc.ResolvePredictionMatchParams{
+		ID:     match.ID,
+		Result: pgtype.Text{String: score, Valid: true},
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if rows == 0 {
+		return match.Name + " has already been resolved", false, nil
+	}
+
+	members, err := b.DB.Queries.WithTx(tx).ListCorrectPredictions(ctx, match.ID)
+	if err != nil {
+		return "", false, err
+	}
+	for _, member := range members {
+		if err := b.DB.Queries.WithTx(tx).UpdateScoreboardForGame(ctx, sqlc.UpdateScoreboardForGameParams{
+			Member: member,
+			Game:   match.Game,
+		}); err != nil {
+			slog.Error("failed to update scoreboard", slog.Int64("member", member), slog.Any("err", err))
+			return "", false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", false, err
+	}
+
+	summary = fmt.Sprintf("%s finished %s, added score for %d members to the %s scoreboard", match.Name, scorelineLabel(score, match.Opponent.String), len(members), match.Game)
+	if len(members) > 0 {
+		summary += "\nCorrect predictions: " + mentionList(members, 50)
+	}
+	return summary, true, nil
+}
+
+// mentionList mentions at most limit members, so the summary stays within
+// the message length limit.
+func mentionList(members []int64, limit int) string {
+	var mentions []string
+	for _, member := range members[:min(len(members), limit)] {
+		mentions = append(mentions, discord.UserMention(snowflake.ID(member)))
+	}
+	list := strings.Join(mentions, ", ")
+	if len(members) > limit {
+		list += fmt.Sprintf(" and %d more", len(members)-limit)
+	}
+	return list
+}
+
+// ResolveAutocompleteHandler suggests open predictions for the match option
+// and the possible scorelines of the chosen match for the score option.
+func ResolveAutocompleteHandler(b *app.Bot) handler.AutocompleteHandler {
+	return func(e *handler.AutocompleteEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		choices := []discord.AutocompleteChoice{}
+		query := strings.ToLower(e.Data.Focused().String())
+		if e.Data.Focused().Name == "score" {
+			matchID, err := strconv.ParseInt(e.Data.String("match"), 10, 64)
+			if err != nil {
+				return e.AutocompleteResult(choices)
+			}
+			match, err := b.DB.Queries.GetPredictionMatch(ctx, matchID)
+			if err != nil {
+				return e.AutocompleteResult(choices)
+			}
+			for _, scoreline := range scorelines(int(match.SeriesLength)) {
+				label := scorelineLabel(scoreline, match.Opponent.String)
+				if strings.Contains(strings.ToLower(label), query) {
+					choices = append(choices, discord.AutocompleteChoiceString{
+						Name:  label,
+						Value: scoreline,
+					})
+				}
+			}
+			return e.AutocompleteResult(choices)
+		}
+
+		matches, err := b.DB.Queries.ListOpenPredictionMatches(ctx)
+		if err != nil {
+			slog.Error("failed to list prediction matches", slog.Any("err", err))
+			return e.AutocompleteResult(choices)
+		}
+		for _, match := range matches {
+			if len(choices) == 25 {
+				break
+			}
+			name := fmt.Sprintf("%s - %s (Bo%d)", match.Game, match.Name, match.SeriesLength)
+			if strings.Contains(strings.ToLower(name), query) {
+				choices = append(choices, discord.AutocompleteChoiceString{
+					Name:  name,
+					Value: strconv.FormatInt(match.ID, 10),
+				})
+			}
+		}
+		return e.AutocompleteResult(choices)
+	}
+}
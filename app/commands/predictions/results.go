@@ -0,0 +1,237 @@
+package predictions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// WatchResults posts the final score of locked predictions for mods to
+// review until ctx is cancelled.
+func WatchResults(ctx context.Context, b *app.Bot) {
+	if b.Cfg.Predictions.ResultPollInterval <= 0 || b.Cfg.Predictions.ReviewChannel == 0 {
+		slog.Info("Prediction result checks disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(b.Cfg.Predictions.ResultPollInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		proposeResults(ctx, b)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func proposeResults(ctx context.Context, b *app.Bot) {
+	// Older predictions are left to /resolve
+	now := time.Now()
+	matches, err := b.DB.Queries.ListUnreviewedPredictionMatches(ctx, sqlc.ListUnreviewedPredictionMatchesParams{
+		Since: now.Add(-72 * time.Hour).Unix(),
+		Until: now.Unix(),
+	})
+	if err != nil {
+		slog.Error("failed to list unreviewed prediction matches", slog.Any("err", err))
+		return
+	}
+
+	for _, match := range matches {
+		score, finished, err := finalScore(ctx, b, match)
+		if err != nil {
+			slog.Error("failed to get final score", slog.Int64("match", match.ID), slog.Any("err", err))
+			continue
+		}
+		if !finished {
+			continue
+		}
+
+		if err := proposeResult(ctx, b, match, score); err != nil {
+			slog.Error("failed to propose prediction result", slog.Int64("match", match.ID), slog.Any("err", err))
+		}
+	}
+}
+
+// finalScore returns the score of the linked Liquipedia match from OG's
+// point of view, once the series is over.
+func finalScore(ctx context.Context, b *app.Bot, match sqlc.PredictionMatch) (string, bool, error) {
+	results, err := b.Liquipedia.FinishedMatches(ctx, match.Wiki.String, time.Unix(match.Deadline, 0).Add(-24*time.Hour))
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, result := range results {
+		if result.Match2ID != match.LiquipediaMatch.String || result.Finished != 1 {
+			continue
+		}
+		us, them, ok := result.Sides(b.Liquipedia.Team(match.Wiki.String))
+		if !ok {
+			return "", false, fmt.Errorf("%s is not playing in %s", b.Liquipedia.Team(match.Wiki.String), result.Match2ID)
+		}
+		return fmt.Sprintf("%d-%d", us.Score, them.Score), true, nil
+	}
+	return "", false, nil
+}
+
+// proposeResult claims the match for review with the score, then posts it.
+// The claim is cleared when the post fails, so it is retried on the next run.
+func proposeResult(ctx context.Context, b *app.Bot, match sqlc.PredictionMatch, score string) error {
+	proposed, err := b.DB.Queries.ProposePredictionResult(ctx, sqlc.ProposePredictionResultParams{
+		ID:             match.ID,
+		ProposedResult: pgtype.Text{String: score, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if proposed == 0 {
+		return nil
+	}
+
+	content := fmt.Sprintf("%s (%s Bo%d) finished %s on Liquipedia.\nConfirm to award points, or pick the correct score.", match.Name, match.Game, match.SeriesLength, scorelineLabel(score, match.Opponent.String))
+	if !slices.Contains(scorelines(int(match.SeriesLength)), score) {
+		content = fmt.Sprintf("%s (%s Bo%d) finished with an unexpected score of %s on Liquipedia.\nPick the correct score to award points.", match.Name, match.Game, match.SeriesLength, score)
+	}
+	if _, err := b.Client.Rest.CreateMessage(b.Cfg.Predictions.ReviewChannel, discord.MessageCreate{
+		Content:    content,
+		Components: reviewComponents(match, score),
+	}); err != nil {
+		if err := b.DB.Queries.ClearProposedPredictionResult(ctx, match.ID); err != nil {
+			slog.Error("failed to clear proposed prediction result", slog.Int64("match", match.ID), slog.Any("err", err))
+		}
+		return fmt.Errorf("failed to post result review: %w", err)
+	}
+	return nil
+}
+
+// reviewComponents offers the proposed score as a button, and every possible
+// score in a menu to override it.
+func reviewComponents(match sqlc.PredictionMatch, score string) []discord.LayoutComponent {
+	var components []discord.LayoutComponent
+	if slices.Contains(scorelines(int(match.SeriesLength)), score) {
+		components = append(components, discord.ActionRowComponent{
+			Components: []discord.InteractiveComponent{
+				discord.ButtonComponent{
+					Label:    "Award " + scorelineLabel(score, match.Opponent.String),
+					Style:    discord.ButtonStyleSuccess,
+					CustomID: fmt.Sprintf("/award/%d/%s", match.ID, score),
+				},
+			},
+		})
+	}
+
+	var options []discord.StringSelectMenuOption
+	for _, scoreline := range scorelines(int(match.SeriesLength)) {
+		options = append(options, discord.StringSelectMenuOption{
+			Label: scorelineLabel(scoreline, match.Opponent.String),
+			Value: scoreline,
+		})
+	}
+	return append(components, discord.ActionRowComponent{
+		Components: []discord.InteractiveComponent{
+			discord.StringSelectMenuComponent{
+				CustomID:    fmt.Sprintf("/award/%d", match.ID),
+				Placeholder: "Override the score",
+				Options:     options,
+			},
+		},
+	})
+}
+
+// AwardComponentHandler confirms the score proposed from Liquipedia.
+func AwardComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		return awardResult(b, e, e.Vars["scoreline"])
+	}
+}
+
+// AwardSelectHandler awards the score a mod picked instead of the proposed one.
+func AwardSelectHandler(b *app.Bot) handler.SelectMenuComponentHandler {
+	return func(data discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+		return awardResult(b, e, data.(discord.StringSelectMenuInteractionData).Values[0])
+	}
+}
+
+// awardResult resolves the reviewed match like /resolve does and announces
+// the result in the predictions channel.
+func awardResult(b *app.Bot, e *handler.ComponentEvent, score string) error {
+	matchID, err := strconv.ParseInt(e.Vars["match"], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	match, err := b.DB.Queries.GetPredictionMatch(ctx, matchID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr("This prediction no longer exists"),
+			Components: &[]discord.LayoutComponent{},
+		})
+	} else if err != nil {
+		slog.Error("failed to get prediction match", slog.Int64("match", matchID), slog.Any("err", err))
+		return err
+	}
+
+	if !slices.Contains(scorelines(int(match.SeriesLength)), score) {
+		return e.CreateMessage(discord.MessageCreate{
+			Content: score + " is not a possible score for this match",
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+
+	if err := e.DeferUpdateMessage(); err != nil {
+		slog.Error("DisGo error(failed to defer update message)", slog.Any("err", err))
+		return err
+	}
+
+	summary, resolved, err := resolveMatch(b, match, score)
+	if err != nil {
+		slog.Error("failed to resolve prediction match", slog.Int64("match", match.ID), slog.Any("err", err))
+		if _, err := e.CreateFollowupMessage(discord.MessageCreate{
+			Content: "Failed to award points, please try again",
+			Flags:   discord.MessageFlagEphemeral,
+		}); err != nil {
+			slog.Error("DisGo error(failed to create followup message)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+
+	if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+		Content:         omit.Ptr(summary + "\nReviewed by " + e.User().Mention()),
+		Components:      &[]discord.LayoutComponent{},
+		AllowedMentions: &discord.AllowedMentions{},
+	}); err != nil {
+		slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+		return err
+	}
+
+	refreshPredictionMessage(b, e.Client(), match.ID)
+
+	if !resolved || b.Cfg.Predictions.Channel == 0 {
+		return nil
+	}
+	if _, err := e.Client().Rest.CreateMessage(b.Cfg.Predictions.Channel, discord.MessageCreate{
+		Content:         summary,
+		AllowedMentions: &discord.AllowedMentions{},
+	}); err != nil {
+		slog.Error("DisGo error(failed to post prediction results)", slog.Any("err", err))
+	}
+	return nil
+}
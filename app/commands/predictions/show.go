@@ -3,20 +3,22 @@ package predictions
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"clockey/app"
 	"clockey/database/sqlc"
-	"database/sql"
 
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/omit"
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/renderer"
 	"github.com/olekukonko/tablewriter/tw"
@@ -58,6 +60,12 @@ var Show = discord.SlashCommandCreate{
 			Description: "The user to show the score for (leave empty to show the full leaderboard)",
 			Required:    false,
 		},
+		discord.ApplicationCommandOptionString{
+			Name:         "season",
+			Description:  "The season to show (leave empty for the current season)",
+			Required:     false,
+			Autocomplete: true,
+		},
 	},
 }
 
@@ -69,375 +77,307 @@ func ShowCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 		}
 
 		game := data.String("game")
-		user, provided := data.OptUser("user")
-
-		if provided && game == "Global" {
-			// Get Global Score for user
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if res, err := b.DB.Queries.GetMemberGlobalScore(ctx, int64(user.ID)); err == nil {
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr(fmt.Sprintf("The global prediction score for %s is %d, ranked at %d", user.Mention(), res.Score, res.Position)),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
-				return nil
-			} else if err == sql.ErrNoRows {
-				// If user's not found
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr(fmt.Sprintf("%s isn't found on the %s scoreboard", user.Mention(), game)),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
-				return nil
-			} else {
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr("Something wrong has happened, please try again"),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
+		season, err := showSeason(b, data)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+				Content: omit.Ptr("That season doesn't exist"),
+			}); err != nil {
+				slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
 				return err
 			}
-		} else if provided && game != "Global" {
-			// Get Game Score for user
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if res, err := b.DB.Queries.GetMemberScoreForGame(ctx, sqlc.GetMemberScoreForGameParams{
-				Game:   sqlc.ScoreboardGame(game),
-				Member: int64(user.ID),
-			}); err == nil {
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr(fmt.Sprintf("The %s prediction score for %s is %d, ranked at %d", game, user.Mention(), res.Score, res.Position)),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
-				return nil
-			} else if err == sql.ErrNoRows {
-				// If user's not found
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr(fmt.Sprintf("%s isn't found on the %s scoreboard", user.Mention(), game)),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
-				return nil
-			} else {
-				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-					Content: omit.Ptr("Something wrong has happened, please try again"),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return err
-				}
-				return err
+			return nil
+		} else if err != nil {
+			slog.Error("failed to get season", slog.Any("err", err))
+			return err
+		}
+
+		if user, provided := data.OptUser("user"); provided {
+			return showMemberScore(b, e, season, game, user)
+		}
+		return generateLeaderboard(b, e, season, game)
+	}
+}
+
+// ShowAutocompleteHandler suggests seasons, newest first.
+func ShowAutocompleteHandler(b *app.Bot) handler.AutocompleteHandler {
+	return func(e *handler.AutocompleteEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		seasons, err := b.DB.Queries.ListSeasons(ctx)
+		if err != nil {
+			slog.Error("failed to list seasons", slog.Any("err", err))
+			return e.AutocompleteResult([]discord.AutocompleteChoice{})
+		}
+
+		query := strings.ToLower(e.Data.Focused().String())
+		choices := []discord.AutocompleteChoice{}
+		for _, season := range seasons {
+			if len(choices) == 25 {
+				break
+			}
+			if strings.Contains(strings.ToLower(season.Name), query) {
+				choices = append(choices, discord.AutocompleteChoiceString{
+					Name:  season.Name,
+					Value: strconv.FormatInt(season.ID, 10),
+				})
 			}
 		}
+		return e.AutocompleteResult(choices)
+	}
+}
 
-		if game == "Global" {
-			return generateGlobalLeaderboard(b, e)
-		} else {
-			return generateGameLeaderboard(b, e, game)
+// showSeason returns the season picked in the command, defaulting to the
+// current one.
+func showSeason(b *app.Bot, data discord.SlashCommandInteractionData) (sqlc.Season, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if value, provided := data.OptString("season"); provided {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return sqlc.Season{}, pgx.ErrNoRows
 		}
+		return b.DB.Queries.GetSeason(ctx, id)
 	}
+	return b.DB.Queries.GetCurrentSeason(ctx)
 }
 
-func generateGameLeaderboard(b *app.Bot, e *handler.CommandEvent, game string) error {
+func showMemberScore(b *app.Bot, e *handler.CommandEvent, season sqlc.Season, game string, user discord.User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	scores, err := b.DB.Queries.ShowScoreboardForGame(ctx, sqlc.ScoreboardGame(game))
-	if err != nil {
+
+	var position, score int64
+	var err error
+	if game == "Global" {
+		var res sqlc.GetMemberGlobalScoreRow
+		res, err = b.DB.Queries.GetMemberGlobalScore(ctx, sqlc.GetMemberGlobalScoreParams{
+			Member: int64(user.ID),
+			Season: season.ID,
+		})
+		position, score = res.Position, res.Score
+	} else {
+		var res sqlc.GetMemberScoreForGameRow
+		res, err = b.DB.Queries.GetMemberScoreForGame(ctx, sqlc.GetMemberScoreForGameParams{
+			Game:   sqlc.ScoreboardGame(game),
+			Member: int64(user.ID),
+			Season: season.ID,
+		})
+		position, score = res.Position, int64(res.Score)
+	}
+
+	label := game
+	if game == "Global" {
+		label = "global"
+	}
+	content := fmt.Sprintf("The %s prediction score for %s in %s is %d, ranked at %d", label, user.Mention(), season.Name, score, position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// If user's not found
+		content = fmt.Sprintf("%s isn't found on the %s scoreboard for %s", user.Mention(), game, season.Name)
+	} else if err != nil {
+		slog.Error("failed to get member score", slog.Any("member", user.ID), slog.Any("err", err))
+		content = "Something wrong has happened, please try again"
+	}
+
+	if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+		Content:         omit.Ptr(content),
+		AllowedMentions: &discord.AllowedMentions{},
+	}); err != nil {
+		slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
 		return err
 	}
+	return err
+}
 
-	var layouts [][]discord.LayoutComponent
-	totalPage := len(scores)/10 + 1
-
-	for i := 1; i <= totalPage; i++ {
-		buf := new(bytes.Buffer)
-		table := tablewriter.NewTable(buf,
-			tablewriter.WithRenderer(renderer.NewMarkdown(tw.Rendition{Borders: tw.Border{
-				Left:  tw.Off,
-				Right: tw.Off,
-			}, Settings: tw.Settings{
-				CompactMode: tw.On,
-			}})),
-			tablewriter.WithAlignment(tw.Alignment{tw.AlignCenter}),
-		)
-		table.Header([]string{"Rank", "Name", "Score"})
-		offset := (i - 1) * 10
-		end := min(offset+10, len(scores))
-
-		for _, score := range scores[offset:end] {
-			// Check if member exists in cache
-			if cachedMember, exists := e.Client().Caches.Member(*e.GuildID(), snowflake.ID(score.Member)); exists {
-				name := truncate(cachedMember.EffectiveName())
-				if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-					slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-					return err
-				}
-			} else {
-				// Make API calls if not in cache
-				if member, err := e.Client().Rest.GetMember(*e.GuildID(), snowflake.ID(score.Member)); err == nil {
-					name := truncate(member.EffectiveName())
-					if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-						slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-						return err
-					}
-				} else {
-					// If not, fetch user info
-					if user, err := e.Client().Rest.GetUser(snowflake.ID(score.Member)); err == nil {
-						name := truncate(user.EffectiveName())
-						if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-							slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-							return err
-						}
-					} else {
-						// Fallback to unknown user
-						name := "Unknown User"
-						if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-							slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-							return err
-						}
-					}
-				}
-			}
+// ShowComponentHandler pages through a leaderboard.
+func ShowComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		game := e.Vars["game"]
+		page, err := strconv.Atoi(e.Vars["page"])
+		if err != nil {
+			return err
 		}
-		if err := table.Render(); err != nil {
-			slog.Error("tablewriter error(failed to render table)", slog.Any("err", err))
+		seasonID, err := strconv.ParseInt(e.Vars["season"], 10, 64)
+		if err != nil {
 			return err
 		}
 
-		layout := []discord.LayoutComponent{
-			discord.TextDisplayComponent{
-				Content: fmt.Sprintf("%s Prediction Leaderboard", game),
-			},
-			discord.SeparatorComponent{},
-			discord.ContainerComponent{
-				AccentColor: 0x00C389,
-				Components: []discord.ContainerSubComponent{
-					discord.TextDisplayComponent{
-						Content: fmt.Sprint("```\n" + buf.String() + "\n```"),
-					},
-				},
-			},
-			discord.ActionRowComponent{
-				Components: []discord.InteractiveComponent{
-					discord.ButtonComponent{
-						Style:    discord.ButtonStyleSecondary,
-						Label:    "⬅️",
-						CustomID: "prev_show",
-					},
-					discord.ButtonComponent{
-						Style:    discord.ButtonStyleSecondary,
-						Label:    "➡️",
-						CustomID: "next_show",
-					},
-				},
-			},
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		season, err := b.DB.Queries.GetSeason(ctx, seasonID)
+		if err != nil {
+			slog.Error("failed to get season", slog.Int64("season", seasonID), slog.Any("err", err))
+			return err
 		}
-		layouts = append(layouts, layout)
 
-	}
+		rows, err := leaderboardRows(b, season, game)
+		if err != nil {
+			slog.Error("failed to get leaderboard", slog.String("game", game), slog.Any("err", err))
+			return err
+		}
 
-	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
-		Components: omit.Ptr(layouts[0]),
-		Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-	})
-	if err != nil {
-		return err
-	}
+		totalPage := max(1, (len(rows)+9)/10)
+		switch e.Vars["direction"] {
+		case "next":
+			page++
+		case "prev":
+			page--
+		}
+		page = (page%totalPage + totalPage) % totalPage
 
-	go func() {
-		ch, cls := bot.NewEventCollector(e.Client(),
-			func(c *events.ComponentInteractionCreate) bool {
-				return c.Data.CustomID() == "next_show" || c.Data.CustomID() == "prev_show"
-			},
-		)
-		defer cls()
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-		currentPage := 0
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case c := <-ch:
-				if c.Data.CustomID() == "next_show_game" {
-					currentPage++
-					if currentPage >= totalPage {
-						currentPage = 0
-					}
-				} else if c.Data.CustomID() == "prev_show_game" {
-					currentPage--
-					if currentPage < 0 {
-						currentPage = totalPage - 1
-					}
-				}
-				if err := c.UpdateMessage(discord.MessageUpdate{
-					Components: omit.Ptr(layouts[currentPage]),
-					Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-					return
-				}
-			default:
-				continue
-			}
+		layout, err := leaderboardLayout(e.Client(), *e.GuildID(), season, game, rows, page)
+		if err != nil {
+			return err
 		}
-	}()
 
-	return nil
+		if err := e.UpdateMessage(discord.MessageUpdate{
+			Components: omit.Ptr(layout),
+			Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
+		}); err != nil {
+			slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+}
+
+type leaderboardRow struct {
+	Position int64
+	Member   int64
+	Score    int64
 }
 
-func generateGlobalLeaderboard(b *app.Bot, e *handler.CommandEvent) error {
+func leaderboardRows(b *app.Bot, season sqlc.Season, game string) ([]leaderboardRow, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	scores, err := b.DB.Queries.ShowGlobalScoreboard(ctx)
+
+	var rows []leaderboardRow
+	if game == "Global" {
+		scores, err := b.DB.Queries.ShowGlobalScoreboard(ctx, season.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, score := range scores {
+			rows = append(rows, leaderboardRow{Position: score.Position, Member: score.Member, Score: score.Score})
+		}
+		return rows, nil
+	}
+
+	scores, err := b.DB.Queries.ShowScoreboardForGame(ctx, sqlc.ShowScoreboardForGameParams{
+		Game:   sqlc.ScoreboardGame(game),
+		Season: season.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, score := range scores {
+		rows = append(rows, leaderboardRow{Position: score.Position, Member: score.Member, Score: int64(score.Score)})
+	}
+	return rows, nil
+}
+
+func generateLeaderboard(b *app.Bot, e *handler.CommandEvent, season sqlc.Season, game string) error {
+	rows, err := leaderboardRows(b, season, game)
 	if err != nil {
 		return err
 	}
 
-	var layouts [][]discord.LayoutComponent
-	totalPage := len(scores)/10 + 1
-
-	for i := 1; i <= totalPage; i++ {
-		buf := new(bytes.Buffer)
-		table := tablewriter.NewTable(buf,
-			tablewriter.WithRenderer(renderer.NewMarkdown()),
-			tablewriter.WithRendition(tw.Rendition{
-				Borders: tw.Border{
-					Left:  tw.Off,
-					Right: tw.Off,
-				},
-			}),
-			tablewriter.WithAlignment(tw.Alignment{tw.AlignCenter}),
-		)
-		table.Header([]string{"Rank", "Name", "Score"})
-		offset := (i - 1) * 10
-		end := min(offset+10, len(scores))
-
-		for _, score := range scores[offset:end] {
-			// Check if member exists in cache
-			if cachedMember, exists := e.Client().Caches.Member(*e.GuildID(), snowflake.ID(score.Member)); exists {
-				name := truncate(cachedMember.EffectiveName())
-				if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-					slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-					return err
-				}
-			} else {
-				// Make API calls if not in cache
-				if member, err := e.Client().Rest.GetMember(*e.GuildID(), snowflake.ID(score.Member)); err == nil {
-					name := truncate(member.EffectiveName())
-					if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-						slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-						return err
-					}
-				} else {
-					// If not, fetch user info
-					if user, err := e.Client().Rest.GetUser(snowflake.ID(score.Member)); err == nil {
-						name := truncate(user.EffectiveName())
-						if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-							slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-							return err
-						}
-					} else {
-						// Fallback to unknown user
-						name := "Unknown User"
-						if err := table.Append([]string{fmt.Sprint(score.Position), name, fmt.Sprint(score.Score)}); err != nil {
-							slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
-							return err
-						}
-					}
-				}
+	layout, err := leaderboardLayout(e.Client(), *e.GuildID(), season, game, rows, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Components:      omit.Ptr(layout),
+		Flags:           omit.Ptr(discord.MessageFlagIsComponentsV2),
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	return err
+}
+
+// leaderboardLayout renders one page of ten rows with the paging buttons.
+// Closed seasons also list their Oracle winners.
+func leaderboardLayout(client *bot.Client, guildID snowflake.ID, season sqlc.Season, game string, rows []leaderboardRow, page int) ([]discord.LayoutComponent, error) {
+	buf := new(bytes.Buffer)
+	table := tablewriter.NewTable(buf,
+		tablewriter.WithRenderer(renderer.NewMarkdown(tw.Rendition{Borders: tw.Border{
+			Left:  tw.Off,
+			Right: tw.Off,
+		}, Settings: tw.Settings{
+			CompactMode: tw.On,
+		}})),
+		tablewriter.WithAlignment(tw.Alignment{tw.AlignCenter}),
+	)
+	table.Header([]string{"Rank", "Name", "Score"})
+	offset := min(page*10, len(rows))
+	end := min(offset+10, len(rows))
+
+	for _, row := range rows[offset:end] {
+		name := truncate(memberName(client, guildID, snowflake.ID(row.Member)))
+		if err := table.Append([]string{fmt.Sprint(row.Position), name, fmt.Sprint(row.Score)}); err != nil {
+			slog.Error("tablewriter error(failed to append row)", slog.Any("err", err))
+			return nil, err
+		}
+	}
+	if err := table.Render(); err != nil {
+		slog.Error("tablewriter error(failed to render table)", slog.Any("err", err))
+		return nil, err
+	}
+
+	title := fmt.Sprintf("%s Prediction Leaderboard - %s", game, season.Name)
+	if season.EndedAt.Valid {
+		var winners []string
+		for _, row := range rows {
+			if row.Position == 1 {
+				winners = append(winners, fmt.Sprintf("<@%d>", row.Member))
 			}
 		}
-		if err := table.Render(); err != nil {
-			slog.Error("tablewriter error(failed to render table)", slog.Any("err", err))
-			return err
+		if len(winners) > 0 {
+			title += "\nOracle: " + strings.Join(winners, ", ")
 		}
+	}
 
-		layout := []discord.LayoutComponent{
-			discord.TextDisplayComponent{
-				Content: "Global Prediction Leaderboard",
-			},
-			discord.SeparatorComponent{},
-			discord.ContainerComponent{
-				Components: []discord.ContainerSubComponent{
-					discord.TextDisplayComponent{
-						Content: fmt.Sprint("```\n" + buf.String() + "\n```"),
-					},
+	return []discord.LayoutComponent{
+		discord.TextDisplayComponent{
+			Content: title,
+		},
+		discord.SeparatorComponent{},
+		discord.ContainerComponent{
+			AccentColor: 0x00C389,
+			Components: []discord.ContainerSubComponent{
+				discord.TextDisplayComponent{
+					Content: fmt.Sprint("```\n" + buf.String() + "\n```"),
 				},
 			},
-			discord.ActionRowComponent{
-				Components: []discord.InteractiveComponent{
-					discord.ButtonComponent{
-						Style:    discord.ButtonStyleSecondary,
-						Label:    "⏮️",
-						CustomID: "prev_show",
-					},
-					discord.ButtonComponent{
-						Style:    discord.ButtonStyleSecondary,
-						Label:    "⏭️",
-						CustomID: "next_show",
-					},
+		},
+		discord.ActionRowComponent{
+			Components: []discord.InteractiveComponent{
+				discord.ButtonComponent{
+					Style:    discord.ButtonStyleSecondary,
+					Label:    "⬅️",
+					CustomID: fmt.Sprintf("/show/%d/%s/%d/prev", season.ID, game, page),
+				},
+				discord.ButtonComponent{
+					Style:    discord.ButtonStyleSecondary,
+					Label:    "➡️",
+					CustomID: fmt.Sprintf("/show/%d/%s/%d/next", season.ID, game, page),
 				},
 			},
-		}
-		layouts = append(layouts, layout)
+		},
+	}, nil
+}
 
+// memberName resolves a display name from the member cache, falling back to
+// the API and finally to the plain user.
+func memberName(client *bot.Client, guildID snowflake.ID, id snowflake.ID) string {
+	if cachedMember, exists := client.Caches.Member(guildID, id); exists {
+		return cachedMember.EffectiveName()
 	}
-
-	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
-		Components: omit.Ptr(layouts[0]),
-		Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-	})
-	if err != nil {
-		return err
+	if member, err := client.Rest.GetMember(guildID, id); err == nil {
+		return member.EffectiveName()
 	}
-
-	go func() {
-		ch, cls := bot.NewEventCollector(e.Client(),
-			func(c *events.ComponentInteractionCreate) bool {
-				return c.Data.CustomID() == "next_show" || c.Data.CustomID() == "prev_show"
-			},
-		)
-		defer cls()
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-		currentPage := 0
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case c := <-ch:
-				if c.Data.CustomID() == "next_show_global" {
-					currentPage++
-					if currentPage >= totalPage {
-						currentPage = 0
-					}
-				} else if c.Data.CustomID() == "prev_show_global" {
-					currentPage--
-					if currentPage < 0 {
-						currentPage = totalPage - 1
-					}
-				}
-				if err := c.UpdateMessage(discord.MessageUpdate{
-					Components: omit.Ptr(layouts[currentPage]),
-					Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-				}); err != nil {
-					slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					return
-				}
-			default:
-				continue
-			}
-		}
-	}()
-
-	return fmt.Errorf("Todo")
+	if user, err := client.Rest.GetUser(id); err == nil {
+		return user.EffectiveName()
+	}
+	return "Unknown User"
 }
 
 func truncate(name string) string {
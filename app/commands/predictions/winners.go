@@ -16,8 +16,9 @@ import (
 )
 
 var Winners = discord.SlashCommandCreate{
-	Name:        "winners",
-	Description: "Give prediction winners their roles and remove previous winners",
+	Name:                     "winners",
+	Description:              "Give prediction winners their roles and remove previous winners",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
 }
 
 func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
@@ -27,11 +28,13 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			return err
 		}
 
+		oracleRoles := b.Cfg.Predictions.OracleRoles
+
 		// Remove previous winners
 		guildMembers := e.Client().Caches.Members(*e.GuildID())
 		for member := range guildMembers {
 			for _, roleID := range member.RoleIDs {
-				if roleID == theOracleRoleID || roleID == dotaOracleRoleID || roleID == csOracleRoleID || roleID == mlbbOracleRoleID || roleID == hokOracleRoleID {
+				if roleID == oracleRoles.Global || roleID == oracleRoles.Dota || roleID == oracleRoles.CS || roleID == oracleRoles.MLBB || roleID == oracleRoles.HoK {
 					// Remove previous winner roles
 					if err := e.Client().Rest.RemoveMemberRole(*e.GuildID(), member.User.ID, roleID); err != nil {
 						slog.Error("DisGo error(failed to remove member role)", slog.Any("err", err))
@@ -44,28 +47,46 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 		defer cancel()
 
+		season, err := b.DB.Queries.GetCurrentSeason(ctx)
+		if err != nil {
+			slog.Error("failed to get current season", slog.Any("err", err))
+			return err
+		}
+
 		// Get winners from database
-		globalWinners, err := b.DB.Queries.GetGlobalWinner(ctx)
+		globalWinners, err := b.DB.Queries.GetGlobalWinner(ctx, season.ID)
 		if err != nil {
 			return err
 		}
 
-		dotaWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.ScoreboardGameDota)
+		dotaWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.GetWinnerForGameParams{
+			Game:   sqlc.ScoreboardGameDota,
+			Season: season.ID,
+		})
 		if err != nil {
 			return err
 		}
 
-		csWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.ScoreboardGameCS)
+		csWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.GetWinnerForGameParams{
+			Game:   sqlc.ScoreboardGameCS,
+			Season: season.ID,
+		})
 		if err != nil {
 			return err
 		}
 
-		mlbbWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.ScoreboardGameMLBB)
+		mlbbWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.GetWinnerForGameParams{
+			Game:   sqlc.ScoreboardGameMLBB,
+			Season: season.ID,
+		})
 		if err != nil {
 			return err
 		}
 
-		hokWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.ScoreboardGameHoK)
+		hokWinners, err := b.DB.Queries.GetWinnerForGame(ctx, sqlc.GetWinnerForGameParams{
+			Game:   sqlc.ScoreboardGameHoK,
+			Season: season.ID,
+		})
 		if err != nil {
 			return err
 		}
@@ -78,7 +99,7 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			} else {
 				replyText += fmt.Sprintf(", <@%d>", winner.Member)
 			}
-			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), theOracleRoleID); err != nil {
+			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), oracleRoles.Global); err != nil {
 				slog.Error("DisGo error(failed to add member role)", slog.Any("err", err))
 				return err
 			}
@@ -90,7 +111,7 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			} else {
 				replyText += fmt.Sprintf(", <@%d>", winner.Member)
 			}
-			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), dotaOracleRoleID); err != nil {
+			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), oracleRoles.Dota); err != nil {
 				slog.Error("DisGo error(failed to add member role)", slog.Any("err", err))
 				return err
 			}
@@ -102,7 +123,7 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			} else {
 				replyText += fmt.Sprintf(", <@%d>", winner.Member)
 			}
-			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), csOracleRoleID); err != nil {
+			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), oracleRoles.CS); err != nil {
 				slog.Error("DisGo error(failed to add member role)", slog.Any("err", err))
 				return err
 			}
@@ -114,7 +135,7 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			} else {
 				replyText += fmt.Sprintf(", <@%d>", winner.Member)
 			}
-			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), mlbbOracleRoleID); err != nil {
+			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), oracleRoles.MLBB); err != nil {
 				slog.Error("DisGo error(failed to add member role)", slog.Any("err", err))
 				return err
 			}
@@ -126,7 +147,7 @@ func WinnersCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 			} else {
 				replyText += fmt.Sprintf(", <@%d>", winner.Member)
 			}
-			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), hokOracleRoleID); err != nil {
+			if err := e.Client().Rest.AddMemberRole(*e.GuildID(), snowflake.ID(winner.Member), oracleRoles.HoK); err != nil {
 				slog.Error("DisGo error(failed to add member role)", slog.Any("err", err))
 				return err
 			}
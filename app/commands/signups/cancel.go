@@ -2,6 +2,7 @@ package signups
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -11,13 +12,16 @@ import (
 
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
 )
 
 var Cancel = discord.MessageCommandCreate{
-	Name: "Cancel Event",
+	Name:                     "Cancel Event",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageEvents),
 	Contexts: []discord.InteractionContextType{
 		discord.InteractionContextTypeGuild,
 	},
@@ -25,11 +29,15 @@ var Cancel = discord.MessageCommandCreate{
 
 func CancelCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 	return func(data discord.MessageCommandInteractionData, e *handler.CommandEvent) error {
-		if !processed(data.TargetMessage()) {
+		event, err := eventForMessage(b, e.Client(), e.Channel().ID(), data.TargetID())
+		if errors.Is(err, pgx.ErrNoRows) {
 			return e.CreateMessage(discord.MessageCreate{
-				Content: "This message has not been processed for signups yet",
+				Content: "This message is not a tracked signup post",
 				Flags:   discord.MessageFlagEphemeral,
 			})
+		} else if err != nil {
+			slog.Error("failed to get event for message", slog.Any("message", data.TargetID()), slog.Any("err", err))
+			return err
 		}
 
 		buttons := discord.ActionRowComponent{
@@ -37,18 +45,18 @@ func CancelCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 				discord.ButtonComponent{
 					Label:    "Yes",
 					Style:    discord.ButtonStyleDanger,
-					CustomID: "cancel_event_yes",
+					CustomID: "/cancel/" + data.TargetID().String() + "/yes",
 				},
 				discord.ButtonComponent{
 					Label:    "No",
 					Style:    discord.ButtonStyleSecondary,
-					CustomID: "cancel_event_no",
+					CustomID: "/cancel/" + data.TargetID().String() + "/no",
 				},
 			},
 		}
 
 		if err := e.CreateMessage(discord.MessageCreate{
-			Content: "Are you sure you want to cancel signups for this event?",
+			Content: fmt.Sprintf("Are you sure you want to cancel signups for %s - %s?", event.Type, event.Name),
 			Components: []discord.LayoutComponent{
 				buttons,
 			},
@@ -57,61 +65,123 @@ func CancelCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 			return err
 		}
 
-		go func() {
-			ch, cls := bot.NewEventCollector(e.Client(),
-				func(c *events.ComponentInteractionCreate) bool {
-					return c.Data.CustomID() == "cancel_event_yes" || c.Data.CustomID() == "cancel_event_no"
-				})
-			defer cls()
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-			select {
-			case <-ctx.Done():
-				return
-			case c := <-ch:
-				if c.Data.CustomID() == "cancel_event_yes" {
-					eventType, name, eventTime, hours, err := parseMessage(data.TargetMessage().Content)
-					if err != nil {
-						slog.Error("failed to parse message", slog.Any("err", err))
-						return
-					}
-
-					if err := b.DB.Queries.DeleteEvent(ctx, sqlc.DeleteEventParams{
-						Type:  eventType,
-						Name:  name,
-						Time:  eventTime,
-						Hours: hours,
-					}); err != nil {
-						if err := c.CreateMessage(discord.MessageCreate{
-							Content: "Error cancelling event, please try again",
-						}); err != nil {
-							slog.Error("DisGo error(failed to create error cancelling event message)", slog.Any("err", err))
-						}
-						return
-					}
-					if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-						Content:    omit.Ptr(fmt.Sprintf("%s - %s cancelled", eventType, name)),
-						Components: &[]discord.LayoutComponent{},
-					}); err != nil {
-						slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
-					}
-
-					if err := c.Client().Rest.RemoveOwnReaction(c.Channel().ID(), data.TargetID(), processedEmoji); err != nil {
-						slog.Error("DisGo error(failed to remove own reaction)", slog.Any("err", err))
-					}
-				} else if c.Data.CustomID() == "cancel_event_no" {
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Content:    omit.Ptr("Event cancellation aborted"),
-						Components: &[]discord.LayoutComponent{},
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-					}
-				} else {
-					return
-				}
-			}
-		}()
+		return nil
+	}
+}
+
+func CancelComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		if e.Vars["action"] != "yes" {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("Event cancellation aborted"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		}
+
+		messageID, err := snowflake.Parse(e.Vars["messageID"])
+		if err != nil {
+			return err
+		}
+
+		event, err := eventForMessage(b, e.Client(), e.Channel().ID(), messageID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("This event has already been cancelled"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		} else if err != nil {
+			slog.Error("failed to get event for message", slog.Any("message", messageID), slog.Any("err", err))
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		gardeners, err := b.DB.Queries.ListEventGardeners(ctx, event.ID)
+		if err != nil {
+			slog.Error("failed to list event gardeners", slog.Int64("event", event.ID), slog.Any("err", err))
+			return err
+		}
+		deleted, err := b.DB.Queries.DeleteEvent(ctx, event.ID)
+		if err != nil {
+			slog.Error("failed to delete event", slog.Int64("event", event.ID), slog.Any("err", err))
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Error cancelling event, please try again",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+		// Another confirmation got there first
+		if deleted == 0 {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("This event has already been cancelled"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		}
 
+		if err := e.DeferUpdateMessage(); err != nil {
+			slog.Error("DisGo error(failed to defer update message)", slog.Any("err", err))
+			return err
+		}
+
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content:    omit.Ptr(cancelEvent(b, e.Client(), *e.GuildID(), event, gardeners)),
+			Components: &[]discord.LayoutComponent{},
+		}); err != nil {
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			return err
+		}
 		return nil
 	}
 }
+
+// cancelEvent cleans up everything attached to a deleted event row and
+// returns a summary for the moderator.
+func cancelEvent(b *app.Bot, client *bot.Client, guildID snowflake.ID, event sqlc.Event, gardeners []int64) string {
+	summary := fmt.Sprintf("%s - %s cancelled", event.Type, event.Name)
+
+	if event.ScheduledEventID.Valid {
+		err := client.Rest.DeleteGuildScheduledEvent(guildID, snowflake.ID(event.ScheduledEventID.Int64))
+		if err != nil && !rest.IsJSONErrorCode(err, rest.JSONErrorCodeUnknownGuildScheduledEvent) {
+			slog.Error("DisGo error(failed to delete scheduled event)", slog.Any("err", err))
+			summary += "\nFailed to delete the scheduled event, please remove it manually"
+		}
+	}
+
+	if len(gardeners) == 0 {
+		return summary
+	}
+
+	channelID := snowflake.ID(event.ChannelID.Int64)
+	if err := client.Rest.RemoveOwnReaction(channelID, snowflake.ID(event.MessageID.Int64), b.Cfg.Signups.ProcessedEmoji); err != nil {
+		slog.Error("DisGo error(failed to remove own reaction)", slog.Any("err", err))
+	}
+
+	notice := fmt.Sprintf("%s - %s at <t:%d:F> has been cancelled, you no longer need to work it", event.Type, event.Name, event.Time)
+	for _, gardener := range gardeners {
+		gardenerID := snowflake.ID(gardener)
+		mention := discord.UserMention(gardenerID)
+		dm, err := client.Rest.CreateDMChannel(gardenerID)
+		if err == nil {
+			_, err = client.Rest.CreateMessage(dm.ID(), discord.MessageCreate{
+				Content: notice,
+			})
+		}
+		if err != nil {
+			// DMs closed, let them know in the signup channel instead
+			slog.Warn("failed to DM gardener about cancellation", slog.Any("gardener", gardenerID), slog.Any("err", err))
+			if _, err := client.Rest.CreateMessage(channelID, discord.MessageCreate{
+				Content: mention + " " + notice,
+				MessageReference: &discord.MessageReference{
+					MessageID: omit.Ptr(snowflake.ID(event.MessageID.Int64)),
+					ChannelID: omit.Ptr(channelID),
+				},
+			}); err != nil {
+				slog.Error("DisGo error(failed to notify gardener)", slog.Any("err", err))
+				summary += "\nFailed to notify " + mention
+				continue
+			}
+		}
+		summary += "\n" + mention + " has been notified"
+	}
+
+	return summary
+}
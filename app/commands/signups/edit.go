@@ -1,20 +1,29 @@
 package signups
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"clockey/app"
+	"clockey/database/sqlc"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/omit"
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
 )
 
 var Edit = discord.SlashCommandCreate{
-	Name:        "edit",
-	Description: "Edit an existing event for Gardeners to sign up for",
+	Name:                     "edit",
+	Description:              "Edit an existing event for Gardeners to sign up for",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageEvents),
 	Options: []discord.ApplicationCommandOption{
 		discord.ApplicationCommandOptionString{
 			Name:        "message_id",
@@ -41,53 +50,173 @@ var Edit = discord.SlashCommandCreate{
 
 func EditCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
-		msg, err := e.Client().Rest.GetMessage(e.Channel().ID(), snowflake.MustParse(data.String("message_id")))
+		messageID, err := snowflake.Parse(data.String("message_id"))
 		if err != nil {
-			slog.Error("DisGo error(failed to get message)", slog.Any("err", err))
+			return e.CreateMessage(discord.MessageCreate{
+				Content: data.String("message_id") + " is not a valid message ID",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		event, err := eventForMessage(b, e.Client(), e.Channel().ID(), messageID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This message is not a tracked signup post",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get event for message", slog.Any("message", messageID), slog.Any("err", err))
 			return err
 		}
-		replyText := "Updated event details: \n"
 
+		updated := event
+		changes := ""
 		if newName, provided := data.OptString("new_name"); provided {
-			nameRegex := regexp.MustCompile(`Event: \w+ - (.+?)(?:\n|$)`)
-			nameMatch := nameRegex.FindStringSubmatch(msg.Content)
-			if len(nameMatch) > 1 {
-				oldName := nameMatch[1]
-				msg.Content = strings.Replace(msg.Content, oldName, newName, 1)
-				replyText += oldName + " -> " + newName + "\n"
-			}
+			updated.Name = newName
+			changes += event.Name + " -> " + newName + "\n"
 		}
 
 		if newTime, provided := data.OptString("new_time"); provided {
-			timeRegex := regexp.MustCompile(`<t:([^:]+):F>`)
-			timeMatch := timeRegex.FindStringSubmatch(msg.Content)
-			if len(timeMatch) > 1 {
-				oldTime := timeMatch[1]
-				msg.Content = strings.Replace(msg.Content, oldTime, newTime, 2)
-				replyText += "<t:" + oldTime + ":F> -> <t:" + newTime + ":F>\n"
+			updated.Time, err = strconv.ParseInt(newTime, 10, 64)
+			if err != nil {
+				return e.CreateMessage(discord.MessageCreate{
+					Content: newTime + " is not a valid unix time. Please try again.",
+					Flags:   discord.MessageFlagEphemeral,
+				})
 			}
+			changes += fmt.Sprintf("<t:%d:F> -> <t:%d:F>\n", event.Time, updated.Time)
 		}
 
 		if newDuration, provided := data.OptString("new_duration"); provided {
-			hoursRegex := regexp.MustCompile(`Hours: (\d+) hours`)
-			hoursMatch := hoursRegex.FindStringSubmatch(msg.Content)
-			if len(hoursMatch) > 1 {
-				oldDuration := hoursMatch[1]
-				msg.Content = hoursRegex.ReplaceAllString(msg.Content, "Hours: "+newDuration+" hours")
-				replyText += oldDuration + " -> " + newDuration + " hours\n"
+			hours, err := strconv.ParseInt(newDuration, 10, 16)
+			if err != nil {
+				return e.CreateMessage(discord.MessageCreate{
+					Content: newDuration + " is not a valid number of hours. Please try again.",
+					Flags:   discord.MessageFlagEphemeral,
+				})
 			}
+			updated.Hours = int16(hours)
+			changes += fmt.Sprintf("%d -> %d hours\n", event.Hours, updated.Hours)
+		}
+
+		if changes == "" {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Nothing to update, provide a new name, time or duration",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		if err := e.DeferCreateMessage(false); err != nil {
+			slog.Error("DisGo error(failed to defer interaction response)", slog.Any("err", err))
+			return err
 		}
 
-		if _, err := e.Client().Rest.UpdateMessage(e.Channel().ID(), snowflake.MustParse(data.String("message_id")), discord.MessageUpdate{
-			Content: &msg.Content,
+		replyText, err := editEvent(b, e, event, updated)
+		if err != nil {
+			slog.Error("failed to edit event", slog.Int64("event", event.ID), slog.Any("err", err))
+			replyText = "Failed to update event: " + err.Error()
+		} else {
+			replyText = "Updated event details: \n" + changes + "\n" + replyText
+		}
+
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: omit.Ptr(replyText),
 		}); err != nil {
-			slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
 			return err
 		}
+		return nil
+	}
+}
+
+// editEvent only commits the database write once both Discord updates went
+// through.
+func editEvent(b *app.Bot, e *handler.CommandEvent, event sqlc.Event, updated sqlc.Event) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	if err := b.DB.Queries.WithTx(tx).UpdateEvent(ctx, sqlc.UpdateEventParams{
+		ID:    updated.ID,
+		Name:  updated.Name,
+		Time:  updated.Time,
+		Hours: updated.Hours,
+	}); err != nil {
+		return "", fmt.Errorf("failed to update database: %w", err)
+	}
+	report := "Database: updated\n"
+
+	scheduledEventUpdated := false
+	if event.ScheduledEventID.Valid {
+		_, err := e.Client().Rest.UpdateGuildScheduledEvent(*e.GuildID(), snowflake.ID(event.ScheduledEventID.Int64), scheduledEventUpdate(updated))
+		if rest.IsJSONErrorCode(err, rest.JSONErrorCodeUnknownGuildScheduledEvent) {
+			report += "Scheduled event: no longer exists\n"
+		} else if err != nil {
+			return "", fmt.Errorf("failed to update scheduled event: %w", err)
+		} else {
+			scheduledEventUpdated = true
+			report += "Scheduled event: updated\n"
+		}
+	} else {
+		report += "Scheduled event: none linked\n"
+	}
 
-		return e.CreateMessage(discord.MessageCreate{
-			Content: replyText,
+	channelID := snowflake.ID(event.ChannelID.Int64)
+	messageID := snowflake.ID(event.MessageID.Int64)
+	msg, err := e.Client().Rest.GetMessage(channelID, messageID)
+	if err == nil {
+		content := editMessageContent(msg.Content, event, updated)
+		_, err = e.Client().Rest.UpdateMessage(channelID, messageID, discord.MessageUpdate{
+			Content: &content,
 		})
+	}
+	if err != nil {
+		// Put the scheduled event back so it matches the rolled back row
+		if scheduledEventUpdated {
+			if _, err := e.Client().Rest.UpdateGuildScheduledEvent(*e.GuildID(), snowflake.ID(event.ScheduledEventID.Int64), scheduledEventUpdate(event)); err != nil {
+				slog.Error("DisGo error(failed to revert scheduled event)", slog.Any("err", err))
+			}
+		}
+		return "", fmt.Errorf("failed to update message: %w", err)
+	}
+	report += "Message: updated"
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit database update: %w", err)
+	}
+	return report, nil
+}
 
+func scheduledEventUpdate(event sqlc.Event) discord.GuildScheduledEventUpdate {
+	start := time.Unix(event.Time, 0)
+	update := discord.GuildScheduledEventUpdate{
+		Name:               string(event.Type) + " - " + event.Name,
+		ScheduledStartTime: &start,
+	}
+	if event.Type == sqlc.EventTypeMLBB || event.Type == sqlc.EventTypeHoK {
+		update.ScheduledEndTime = omit.Ptr(start.Add(time.Duration(event.Hours) * time.Hour))
 	}
+	return update
+}
+
+func editMessageContent(content string, event sqlc.Event, updated sqlc.Event) string {
+	content = strings.Replace(content,
+		"Event: "+string(event.Type)+" - "+event.Name,
+		"Event: "+string(updated.Type)+" - "+updated.Name, 1)
+	content = strings.ReplaceAll(content,
+		"<t:"+strconv.FormatInt(event.Time, 10)+":",
+		"<t:"+strconv.FormatInt(updated.Time, 10)+":")
+	content = strings.Replace(content,
+		fmt.Sprintf("Hours: %d hours", event.Hours),
+		fmt.Sprintf("Hours: %d hours", updated.Hours), 1)
+	return content
 }
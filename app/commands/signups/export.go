@@ -0,0 +1,182 @@
+package signups
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/go-pdf/fpdf"
+)
+
+// sendReportExport attaches the CSV of every worked event and an invoice per
+// gardener to follow-up messages, as Discord allows 10 files per message.
+func sendReportExport(b *app.Bot, e *handler.CommandEvent, startDate time.Time, endDate time.Time) error {
+	reported, err := gardenerReports(b, startDate, endDate)
+	if err != nil {
+		slog.Error("failed to list gardeners", slog.Any("err", err))
+		return err
+	}
+
+	rates, err := listRates(b)
+	if err != nil {
+		slog.Error("failed to list hourly rates", slog.Any("err", err))
+		return err
+	}
+
+	files, err := reportExportFiles(b.Cfg.Signups, reported, rates, startDate, endDate)
+	if err != nil {
+		slog.Error("failed to export report", slog.Any("err", err))
+		if _, err := e.CreateFollowupMessage(discord.MessageCreate{
+			Content: "Failed to export the report, please try again",
+			Flags:   discord.MessageFlagEphemeral,
+		}); err != nil {
+			slog.Error("DisGo error(failed to create followup message)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+
+	for chunk := range slices.Chunk(files, 10) {
+		if _, err := e.CreateFollowupMessage(discord.MessageCreate{
+			Files: chunk,
+			Flags: discord.MessageFlagEphemeral,
+		}); err != nil {
+			slog.Error("DisGo error(failed to send report export)", slog.Any("err", err))
+			return err
+		}
+	}
+	return nil
+}
+
+// reportExportFiles builds the CSV with a row per gardener and event, and the
+// PDF invoices.
+func reportExportFiles(cfg app.SignupsConfig, reported []GardenerReportResult, rates []sqlc.HourlyRate, startDate time.Time, endDate time.Time) ([]*discord.File, error) {
+	period := startDate.Format("2006-01-02") + "_" + endDate.Format("2006-01-02")
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write([]string{"date", "game", "name", "hours", "gardener", "rate", "amount", "currency"}); err != nil {
+		return nil, err
+	}
+	var invoices []*discord.File
+	for _, invoice := range reported {
+		if len(invoice.Events) == 0 {
+			continue
+		}
+		for _, event := range invoice.Events {
+			var rate, amount string
+			if cents, ok := rateFor(rates, invoice.Gardener.ID, event); ok {
+				rate = centsString(int64(cents))
+				amount = centsString(int64(cents) * int64(event.Hours))
+			}
+			if err := w.Write([]string{
+				time.Unix(event.Time, 0).UTC().Format("2006-01-02"),
+				string(event.Type),
+				event.Name,
+				strconv.Itoa(int(event.Hours)),
+				invoice.Gardener.Name,
+				rate,
+				amount,
+				currency(cfg),
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		pdf, err := gardenerInvoicePDF(cfg, invoice, rates, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render invoice of %s: %w", invoice.Gardener.Name, err)
+		}
+		invoices = append(invoices, discord.NewFile(fmt.Sprintf("invoice_%s_%s.pdf", fileSafe(invoice.Gardener.Name), period), invoice.Gardener.Name+"'s invoice", pdf))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return append([]*discord.File{discord.NewFile("report_"+period+".csv", "Events worked", buf)}, invoices...), nil
+}
+
+// gardenerInvoicePDF renders the invoice of one gardener as a table of the
+// events worked with the total hours and amount.
+func gardenerInvoicePDF(cfg app.SignupsConfig, invoice GardenerReportResult, rates []sqlc.HourlyRate, startDate time.Time, endDate time.Time) (*bytes.Buffer, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	// The core fonts only cover cp1252, anything else is replaced
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, tr("Invoice - "+invoice.Gardener.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 7, fmt.Sprintf("Period: %s - %s", startDate.Format("02 Jan 2006"), endDate.Format("02 Jan 2006")), "", 1, "L", false, 0, "")
+	pdf.Ln(5)
+
+	widths := []float64{25, 15, 90, 15, 22, 23}
+	pdf.SetFont("Helvetica", "B", 11)
+	for i, header := range []string{"Date", "Game", "Event", "Hours", "Rate", "Amount"} {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "L", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 11)
+	totalHours, totalCost := 0, int64(0)
+	for _, event := range invoice.Events {
+		rate, amount := "-", "-"
+		if cents, ok := rateFor(rates, invoice.Gardener.ID, event); ok {
+			rate = centsString(int64(cents))
+			amount = centsString(int64(cents) * int64(event.Hours))
+			totalCost += int64(cents) * int64(event.Hours)
+		}
+		pdf.CellFormat(widths[0], 7, time.Unix(event.Time, 0).UTC().Format("02 Jan 2006"), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, string(event.Type), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, fitCell(pdf, tr(event.Name), widths[2]), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 7, strconv.Itoa(int(event.Hours)), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 7, rate, "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[5], 7, amount, "1", 1, "R", false, 0, "")
+		totalHours += int(event.Hours)
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 8, "Total", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 8, strconv.Itoa(totalHours), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4]+widths[5], 8, formatMoney(totalCost, cfg), "1", 1, "R", false, 0, "")
+
+	buf := &bytes.Buffer{}
+	if err := pdf.Output(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// fitCell shortens text that would overflow a cell of the given width.
+func fitCell(pdf *fpdf.Fpdf, text string, width float64) string {
+	width -= 2 * pdf.GetCellMargin()
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// fileSafe keeps letters and digits of a name for use in a file name.
+func fileSafe(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
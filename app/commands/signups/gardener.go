@@ -2,9 +2,11 @@ package signups
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	"clockey/app"
@@ -12,13 +14,15 @@ import (
 
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
 )
 
 var Gardener = discord.MessageCommandCreate{
-	Name: "Roll Gardener",
+	Name:                     "Roll Gardener",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageEvents),
 	Contexts: []discord.InteractionContextType{
 		discord.InteractionContextTypeGuild,
 	},
@@ -26,8 +30,26 @@ var Gardener = discord.MessageCommandCreate{
 
 func GardenerCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 	return func(data discord.MessageCommandInteractionData, e *handler.CommandEvent) error {
+		event, err := eventForMessage(b, e.Client(), e.Channel().ID(), data.TargetID())
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This message is not a tracked signup post",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get event for message", slog.Any("message", data.TargetID()), slog.Any("err", err))
+			return err
+		}
+
 		// Check if message has already been processed
-		if processed(data.TargetMessage()) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assigned, err := b.DB.Queries.ListEventGardeners(ctx, event.ID)
+		if err != nil {
+			slog.Error("failed to list event gardeners", slog.Int64("event", event.ID), slog.Any("err", err))
+			return err
+		}
+		if len(assigned) > 0 {
 			return e.CreateMessage(discord.MessageCreate{
 				Content: "This message has been processed for signups",
 				Flags:   discord.MessageFlagEphemeral,
@@ -35,19 +57,40 @@ func GardenerCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 		}
 
 		// Show gardener selection menu
-		gardenerSelectMenu, err := gardenerSelectMenuBuilder(e, data.TargetMessage())
+		gardenerSelectMenu, signups, err := gardenerSelectMenuBuilder(b, e, data.TargetMessage())
 		if err != nil {
 			slog.Error("DisGo error(failed to build gardener select menu)", slog.Any("err", err))
 			return err
 		}
+		if signups == 0 {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Nobody has signed up for this event yet",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		var content string
+		if signups > len(gardenerSelectMenu.Options) {
+			content = fmt.Sprintf("Only the first %d of %d signups fit in the menu, the roll still picks from everyone. Use /manual for anyone else", len(gardenerSelectMenu.Options), signups)
+		}
 
 		if err := e.CreateMessage(discord.MessageCreate{
+			Content: content,
 			Components: []discord.LayoutComponent{
 				discord.ActionRowComponent{
 					Components: []discord.InteractiveComponent{
 						gardenerSelectMenu,
 					},
 				},
+				discord.ActionRowComponent{
+					Components: []discord.InteractiveComponent{
+						discord.ButtonComponent{
+							Label:    "Roll (" + rollPolicy(b) + ")",
+							Style:    discord.ButtonStylePrimary,
+							CustomID: "/roll/" + data.TargetID().String() + "/auto",
+						},
+					},
+				},
 			},
 			Flags: discord.MessageFlagEphemeral,
 		}); err != nil {
@@ -55,104 +98,228 @@ func GardenerCommandHandler(b *app.Bot) handler.MessageCommandHandler {
 			return err
 		}
 
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-			bot.WaitForEvent(e.Client(), ctx,
-				func(s *events.ComponentInteractionCreate) bool {
-					return s.Data.CustomID() == "gardener_select_menu"
-				},
-				func(s *events.ComponentInteractionCreate) {
-					selectedGardenerID := s.Data.(discord.StringSelectMenuInteractionData).Values[0]
-					gardenerID, _ := strconv.ParseInt(selectedGardenerID, 10, 64)
-
-					eventType, name, eventTime, hours, err := parseMessage(data.TargetMessage().Content)
-					if err != nil {
-						slog.Error("failed to parse message", slog.Any("err", err))
-						return
-					}
-
-					if err := b.DB.Queries.CreateEvent(ctx, sqlc.CreateEventParams{
-						Type:     eventType,
-						Name:     name,
-						Time:     eventTime,
-						Hours:    hours,
-						Gardener: gardenerID,
-					}); err != nil {
-						slog.Error("failed to create event in database", slog.Any("err", err))
-						return
-					}
-
-					if err := s.Client().Rest.AddReaction(data.TargetMessage().ChannelID, data.TargetMessage().ID, processedEmoji); err != nil {
-						slog.Error("DisGo error(failed to add reaction)", slog.Any("err", err))
-					}
-
-					if err := s.UpdateMessage(discord.MessageUpdate{
-						Content:    omit.Ptr("Hours added to the database"),
-						Components: &[]discord.LayoutComponent{},
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
-					}
-
-					if _, err := s.Client().Rest.CreateMessage(s.Message.ChannelID, discord.MessageCreate{
-						MessageReference: &discord.MessageReference{
-							Type:      discord.MessageReferenceTypeForward,
-							MessageID: omit.Ptr(data.TargetMessage().ID),
-							ChannelID: omit.Ptr(data.TargetMessage().ChannelID),
-						},
-					}); err != nil {
-						slog.Error("DisGo error(failed to send message reference)", slog.Any("err", err))
-					}
+		return nil
+	}
+}
 
-					if _, err := s.Client().Rest.CreateMessage(s.Message.ChannelID, discord.MessageCreate{
-						Content: "<@" + selectedGardenerID + "> will be working " + name,
-					}); err != nil {
-						slog.Error("DisGo error(failed to send message)", slog.Any("err", err))
-					}
+// GardenerComponentHandler handles the select menu sent by Roll Gardener.
+func GardenerComponentHandler(b *app.Bot) handler.SelectMenuComponentHandler {
+	return func(data discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+		messageID, err := snowflake.Parse(e.Vars["messageID"])
+		if err != nil {
+			return err
+		}
 
-				},
-				func() {
-					if err := e.CreateMessage(discord.MessageCreate{
-						Content: "Gardener selection timed out.",
-						Flags:   discord.MessageFlagEphemeral,
-					}); err != nil {
-						slog.Error("DisGo error(failed to send timeout message)", slog.Any("err", err))
-					}
-				},
-			)
-		}()
+		event, ok, err := unassignedEvent(b, e, messageID)
+		if err != nil || !ok {
+			return err
+		}
 
-		return nil
+		var gardenerIDs []int64
+		for _, value := range data.(discord.StringSelectMenuInteractionData).Values {
+			gardenerID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			gardenerIDs = append(gardenerIDs, gardenerID)
+		}
+
+		return assignGardeners(b, e, event, gardenerIDs, "Hours added to the database")
 	}
 }
 
-func gardenerSelectMenuBuilder(e *handler.CommandEvent, msg discord.Message) (discord.StringSelectMenuComponent, error) {
-	gardenersReacted, err := e.Client().Rest.GetReactions(msg.ChannelID, msg.ID, signupEmoji, discord.MessageReactionTypeNormal, 0, 6)
+// unassignedEvent returns the event of the signup message, replying instead
+// when it was cancelled or already has gardeners.
+func unassignedEvent(b *app.Bot, e *handler.ComponentEvent, messageID snowflake.ID) (sqlc.Event, bool, error) {
+	event, err := eventForMessage(b, e.Client(), e.Channel().ID(), messageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return event, false, e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr("This event has been cancelled"),
+			Components: &[]discord.LayoutComponent{},
+		})
+	} else if err != nil {
+		slog.Error("failed to get event for message", slog.Any("message", messageID), slog.Any("err", err))
+		return event, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assigned, err := b.DB.Queries.ListEventGardeners(ctx, event.ID)
 	if err != nil {
-		return discord.StringSelectMenuComponent{}, err
+		slog.Error("failed to list event gardeners", slog.Int64("event", event.ID), slog.Any("err", err))
+		return event, false, err
+	}
+	if len(assigned) > 0 {
+		return event, false, e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr("This message has been processed for signups"),
+			Components: &[]discord.LayoutComponent{},
+		})
 	}
+	return event, true, nil
+}
 
-	for idx, gardener := range gardenersReacted {
-		if gardener.ID == e.Client().ApplicationID {
-			gardenersReacted = append(gardenersReacted[:idx], gardenersReacted[idx+1:]...)
+// assignGardeners stores the gardeners working the event, marks the signup
+// message as processed and announces the pick in the channel.
+func assignGardeners(b *app.Bot, e *handler.ComponentEvent, event sqlc.Event, gardenerIDs []int64, content string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		slog.Error("failed to begin transaction", slog.Any("err", err))
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
 		}
+	}()
+
+	// Lock the event so concurrent picks see each other's gardeners
+	if _, err := b.DB.Queries.WithTx(tx).LockEvent(ctx, event.ID); errors.Is(err, pgx.ErrNoRows) {
+		return e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr("This event has been cancelled"),
+			Components: &[]discord.LayoutComponent{},
+		})
+	} else if err != nil {
+		slog.Error("failed to lock event", slog.Int64("event", event.ID), slog.Any("err", err))
+		return err
 	}
+	assigned, err := b.DB.Queries.WithTx(tx).ListEventGardeners(ctx, event.ID)
+	if err != nil {
+		slog.Error("failed to list event gardeners", slog.Int64("event", event.ID), slog.Any("err", err))
+		return err
+	}
+	if len(assigned) > 0 {
+		return e.UpdateMessage(discord.MessageUpdate{
+			Content:    omit.Ptr("This message has been processed for signups"),
+			Components: &[]discord.LayoutComponent{},
+		})
+	}
+
+	var mentions []string
+	for _, gardenerID := range gardenerIDs {
+		if err := b.DB.Queries.WithTx(tx).AddEventGardener(ctx, sqlc.AddEventGardenerParams{
+			Event:    event.ID,
+			Gardener: gardenerID,
+		}); err != nil {
+			slog.Error("failed to add event gardener to database", slog.Any("err", err))
+			return err
+		}
+		mentions = append(mentions, discord.UserMention(snowflake.ID(gardenerID)))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("failed to commit transaction", slog.Any("err", err))
+		return err
+	}
+
+	channelID := snowflake.ID(event.ChannelID.Int64)
+	messageID := snowflake.ID(event.MessageID.Int64)
+	if err := e.Client().Rest.AddReaction(channelID, messageID, b.Cfg.Signups.ProcessedEmoji); err != nil {
+		slog.Error("DisGo error(failed to add reaction)", slog.Any("err", err))
+	}
+
+	if err := e.UpdateMessage(discord.MessageUpdate{
+		Content:         omit.Ptr(content),
+		Components:      &[]discord.LayoutComponent{},
+		AllowedMentions: &discord.AllowedMentions{},
+	}); err != nil {
+		slog.Error("DisGo error(failed to update message)", slog.Any("err", err))
+	}
+
+	if _, err := e.Client().Rest.CreateMessage(e.Channel().ID(), discord.MessageCreate{
+		MessageReference: &discord.MessageReference{
+			Type:      discord.MessageReferenceTypeForward,
+			MessageID: omit.Ptr(messageID),
+			ChannelID: omit.Ptr(channelID),
+		},
+	}); err != nil {
+		slog.Error("DisGo error(failed to send message reference)", slog.Any("err", err))
+	}
+
+	if _, err := e.Client().Rest.CreateMessage(e.Channel().ID(), discord.MessageCreate{
+		Content: fmt.Sprintf("%s will be working %s", joinMentions(mentions), event.Name),
+	}); err != nil {
+		slog.Error("DisGo error(failed to send message)", slog.Any("err", err))
+	}
+
+	return nil
+}
+
+// gardenerSelectMenuBuilder also returns how many gardeners signed up, as
+// only the first 25 fit in the menu.
+func gardenerSelectMenuBuilder(b *app.Bot, e *handler.CommandEvent, msg discord.Message) (discord.StringSelectMenuComponent, int, error) {
+	gardeners, err := signedUpGardeners(b, e.Client(), msg.ChannelID, msg.ID)
+	if err != nil {
+		return discord.StringSelectMenuComponent{}, 0, err
+	}
+
 	gardenerSelectMenuOptions := []discord.StringSelectMenuOption{}
+	for _, gardener := range gardeners {
+		gardenerSelectMenuOptions = append(gardenerSelectMenuOptions, discord.StringSelectMenuOption{
+			Label: gardener.Name,
+			Value: strconv.FormatInt(gardener.ID, 10),
+		})
+	}
+	// Discord caps select menus at 25 options
+	gardenerSelectMenuOptions = gardenerSelectMenuOptions[:min(len(gardenerSelectMenuOptions), 25)]
+
+	return discord.StringSelectMenuComponent{
+		CustomID:    "/roll/" + msg.ID.String(),
+		Placeholder: "Select the gardeners working this event",
+		MinValues:   omit.Ptr(1),
+		MaxValues:   max(len(gardenerSelectMenuOptions), 1),
+		Options:     gardenerSelectMenuOptions,
+	}, len(gardeners), nil
+}
 
+// signedUpGardeners returns the gardeners who reacted to the signup message,
+// in the order they signed up. Reactors who aren't gardeners are skipped.
+func signedUpGardeners(b *app.Bot, client *bot.Client, channelID snowflake.ID, messageID snowflake.ID) ([]sqlc.Gardener, error) {
+	var gardenersReacted []discord.User
+	after := 0
+	for {
+		page, err := client.Rest.GetReactions(channelID, messageID, b.Cfg.Signups.SignupEmoji, discord.MessageReactionTypeNormal, after, 100)
+		if err != nil {
+			return nil, err
+		}
+		gardenersReacted = append(gardenersReacted, page...)
+		if len(page) < 100 {
+			break
+		}
+		after = int(page[len(page)-1].ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	gardeners, err := b.DB.Queries.ListGardeners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gardenerNames := make(map[int64]string, len(gardeners))
+	for _, gardener := range gardeners {
+		gardenerNames[gardener.ID] = gardener.Name
+	}
+
+	var signedUp []sqlc.Gardener
 	for _, gardener := range gardenersReacted {
-		if name, exists := gardenerIDsMap[gardener.ID]; exists {
-			gardenerSelectMenuOptions = append(gardenerSelectMenuOptions, discord.StringSelectMenuOption{
-				Label: name,
-				Value: gardener.ID.String(),
-			})
+		if gardener.ID == client.ApplicationID {
+			continue
+		}
+		if name, exists := gardenerNames[int64(gardener.ID)]; exists {
+			signedUp = append(signedUp, sqlc.Gardener{ID: int64(gardener.ID), Name: name, Active: true})
 		} else {
-			return discord.StringSelectMenuComponent{}, fmt.Errorf("unknown gardener ID: %d", gardener.ID)
+			slog.Warn("skipping signup from unknown gardener", slog.Any("user", gardener.ID), slog.Any("message", messageID))
 		}
 	}
+	return signedUp, nil
+}
 
-	return discord.StringSelectMenuComponent{
-		CustomID:    "gardener_select_menu",
-		Placeholder: "Select the gardener working this event",
-		Options:     gardenerSelectMenuOptions,
-	}, nil
+// joinMentions lists mentions as "a", "a and b" or "a, b and c".
+func joinMentions(mentions []string) string {
+	if len(mentions) <= 1 {
+		return strings.Join(mentions, "")
+	}
+	return strings.Join(mentions[:len(mentions)-1], ", ") + " and " + mentions[len(mentions)-1]
 }
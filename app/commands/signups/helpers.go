@@ -1,144 +1,110 @@
 package signups
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"regexp"
 	"strconv"
-	"strings"
+	"time"
 
+	"clockey/app"
 	"clockey/database/sqlc"
 
+	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/omit"
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type ChannelConfig struct {
-	VoiceChannels    map[string]snowflake.ID
-	ExternalChannels map[string]string
-	StageChannel     snowflake.ID
-}
-
-// Production channel and role IDs
-var channels = ChannelConfig{
-	VoiceChannels: map[string]snowflake.ID{
-		"Dota": snowflake.ID(738009797932351519),
-		"CS":   snowflake.ID(746618267434614804),
-	},
-	ExternalChannels: map[string]string{
-		"MLBB": "https://discord.com/channels/689865753662455829/1350252799019188236",
-		"HoK":  "https://discord.com/channels/689865753662455829/1344676860562509955",
-	},
-	StageChannel: snowflake.ID(1186593338300842025),
-}
-
-const (
-	gardenerRoleID = snowflake.ID(720253636797530203)
-	signupEmoji    = "OGpeepoYes:730890894814740541"
-	processedEmoji = "OGwecoo:787697278190223370"
-)
-
-// Dev channel and role IDs
-// var channels = ChannelConfig{
-// 	VoiceChannels: map[string]snowflake.ID{
-// 		"Dota": snowflake.ID(1435509993947398206),
-// 		"CS":   snowflake.ID(1435510029846446080),
-// 	},
-// 	ExternalChannels: map[string]string{
-// 		"MLBB": "https://discord.com/channels/738607619660578876/738607620566286397",
-// 		"HoK":  "https://discord.com/channels/738607619660578876/940936483819380757",
-// 	},
-// 	StageChannel: snowflake.ID(991620472544440454),
-// }
-
-// const (
-// 	gardenerRoleID = snowflake.ID(1435510452795871232)
-// 	signupEmoji    = "khezuBrain:1329032244580323349"
-// 	processedEmoji = "ruggahPain:951843834554376262"
-// )
-
-var gardenerIDsMap = map[snowflake.ID]string{
-	293360731867316225: "N1k",
-	204923365205475329: "Kit",
-	492549065041510403: "Pupi",
-	754724309276164159: "WW",
-	172360818715918337: "Bonteng",
-	332438787588227072: "Sam",
-}
-
-var eventModal = discord.ModalCreate{
-	CustomID: "event_modal",
-	Title:    "Event Modal",
-	Components: []discord.LayoutComponent{
-		discord.LabelComponent{
-			Label:       "Event Type",
-			Description: "Select the type of event",
-			Component: discord.StringSelectMenuComponent{
-				CustomID: "event_type",
-				Options: []discord.StringSelectMenuOption{
-					{
-						Label: "Dota",
-						Value: "Dota",
-					},
-					{
-						Label: "CS",
-						Value: "CS",
-					},
-					{
-						Label: "MLBB",
-						Value: "MLBB",
-					},
-					{
-						Label: "HoK",
-						Value: "HoK",
-					},
-					{
-						Label: "Other",
-						Value: "Other",
+func eventModal(customID string) discord.ModalCreate {
+	return discord.ModalCreate{
+		CustomID: customID,
+		Title:    "Event Modal",
+		Components: []discord.LayoutComponent{
+			discord.LabelComponent{
+				Label:       "Event Type",
+				Description: "Select the type of event",
+				Component: discord.StringSelectMenuComponent{
+					CustomID: "event_type",
+					Options: []discord.StringSelectMenuOption{
+						{
+							Label: "Dota",
+							Value: "Dota",
+						},
+						{
+							Label: "CS",
+							Value: "CS",
+						},
+						{
+							Label: "MLBB",
+							Value: "MLBB",
+						},
+						{
+							Label: "HoK",
+							Value: "HoK",
+						},
+						{
+							Label: "Other",
+							Value: "Other",
+						},
 					},
+					Required: true,
 				},
-				Required: true,
 			},
-		},
-		discord.LabelComponent{
-			Label:       "Event Name",
-			Description: "Enter the name of the event",
-			Component: discord.TextInputComponent{
-				CustomID:    "event_name",
-				Style:       discord.TextInputStyleShort,
-				Placeholder: "OG vs <opp team name>",
-				Required:    true,
+			discord.LabelComponent{
+				Label:       "Event Name",
+				Description: "Enter the name of the event",
+				Component: discord.TextInputComponent{
+					CustomID:    "event_name",
+					Style:       discord.TextInputStyleShort,
+					Placeholder: "OG vs <opp team name>",
+					Required:    true,
+				},
 			},
-		},
-		discord.LabelComponent{
-			Label:       "Event Schedule",
-			Description: "Enter the unix time for the start of this event",
-			Component: discord.TextInputComponent{
-				CustomID:    "event_time",
-				Style:       discord.TextInputStyleShort,
-				Required:    true,
-				Placeholder: "Insert unix time from hammertime here",
+			discord.LabelComponent{
+				Label:       "Event Schedule",
+				Description: "Enter the unix time for the start of this event",
+				Component: discord.TextInputComponent{
+					CustomID:    "event_time",
+					Style:       discord.TextInputStyleShort,
+					Required:    true,
+					Placeholder: "Insert unix time from hammertime here",
+				},
 			},
-		},
-		discord.LabelComponent{
-			Label:       "Event duration",
-			Description: "How many hours is this event",
-			Component: discord.TextInputComponent{
-				CustomID: "event_duration",
-				Style:    discord.TextInputStyleShort,
-				Required: true,
+			discord.LabelComponent{
+				Label:       "Event duration",
+				Description: "How many hours is this event",
+				Component: discord.TextInputComponent{
+					CustomID: "event_duration",
+					Style:    discord.TextInputStyleShort,
+					Required: true,
+				},
 			},
-		},
-		discord.LabelComponent{
-			Label:       "Event Banner",
-			Description: "The banner for this event (if any, 800x320 px in size). ",
-			Component: discord.FileUploadComponent{
-				CustomID: "event_banner",
-				Required: false,
+			discord.LabelComponent{
+				Label:       "Event Banner",
+				Description: "The banner for this event (if any, 800x320 px in size). ",
+				Component: discord.FileUploadComponent{
+					CustomID: "event_banner",
+					Required: false,
+				},
 			},
 		},
-	},
+	}
+}
+
+// signupContent is the text of a signup post that gardeners react to.
+func signupContent(cfg app.SignupsConfig, eventType string, name string, unix int64, hours int16) string {
+	return fmt.Sprintf("Hey <@&%s>\n\n"+
+		"Event: %s - %s\n"+
+		"Time: <t:%d:F> (<t:%d:R>)\n"+
+		"Hours: %d hours\n"+
+		"Please react with <:%s> to sign up!.", cfg.GardenerRoleID, eventType, name, unix, unix, hours, cfg.SignupEmoji)
 }
 
 func getBanner(attachment discord.Attachment) *discord.Icon {
@@ -158,64 +124,102 @@ func getBanner(attachment discord.Attachment) *discord.Icon {
 	return banner
 }
 
-func processed(msg discord.Message) bool {
-	for _, reaction := range msg.Reactions {
-		if reaction.Emoji.Reaction() == processedEmoji {
-			return true
+// createScheduledEvent creates the guild scheduled event matching a signup
+// post, using the channel configured for the event type.
+func createScheduledEvent(client *bot.Client, guildID snowflake.ID, cfg app.SignupsConfig, eventType string, name string, start time.Time, hours int16, banner *discord.Icon) (*discord.GuildScheduledEvent, error) {
+	scheduledEvent := discord.GuildScheduledEventCreate{
+		Name:               eventType + " - " + name,
+		PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
+		ScheduledStartTime: start,
+		Image:              banner,
+	}
+
+	switch eventType {
+	case "Dota", "CS":
+		scheduledEvent.EntityType = discord.ScheduledEventEntityTypeVoice
+		scheduledEvent.ChannelID = cfg.VoiceChannels[eventType]
+	case "MLBB", "HoK":
+		scheduledEvent.EntityType = discord.ScheduledEventEntityTypeExternal
+		scheduledEvent.EntityMetaData = &discord.EntityMetaData{
+			Location: cfg.ExternalChannels[eventType],
 		}
+		scheduledEvent.ScheduledEndTime = omit.Ptr(start.Add(time.Duration(hours) * time.Hour))
+	case "Other":
+		scheduledEvent.EntityType = discord.ScheduledEventEntityTypeStageInstance
+		scheduledEvent.ChannelID = cfg.StageChannel
+	default:
+		return nil, fmt.Errorf("invalid event type: %s", eventType)
 	}
-	return false
+
+	return client.Rest.CreateGuildScheduledEvent(guildID, scheduledEvent)
 }
 
-func parseMessage(msg string) (sqlc.EventType, string, int64, int16, error) {
-	var eventType sqlc.EventType
-	if strings.Contains(msg, "Dota") {
-		eventType = sqlc.EventTypeDota
-	} else if strings.Contains(msg, "CS") {
-		eventType = sqlc.EventTypeCS
-	} else if strings.Contains(msg, "MLBB") {
-		eventType = sqlc.EventTypeMLBB
-	} else if strings.Contains(msg, "HoK") {
-		eventType = sqlc.EventTypeHoK
-	} else if strings.Contains(msg, "Other") {
-		eventType = sqlc.EventTypeOther
-	} else {
-		return "", "", 0, 0, fmt.Errorf("failed to parse event type")
-	}
+// parseEndDate parses a DD-MM-YYYY date as the end of a period, which
+// includes the whole day.
+func parseEndDate(value string) (time.Time, error) {
+	endDate, err := time.Parse("02-01-2006", value)
+	return endDate.Add(24*time.Hour - time.Second), err
+}
 
-	var name string
-	nameRegex := regexp.MustCompile(`Event: \w+ - (.+?)(?:\n|$)`)
-	nameMatch := nameRegex.FindStringSubmatch(msg)
-	if len(nameMatch) > 1 {
-		name = nameMatch[1]
-	} else {
-		return "", "", 0, 0, fmt.Errorf("failed to parse event name")
+var legacySignupRegex = regexp.MustCompile(`Event: (Dota|CS|MLBB|HoK|Other) - (.+)\nTime: <t:(\d+):F>.*\nHours: (\d+) hours`)
+
+// eventForMessage returns the event tracked for the given signup message.
+// Older events are matched on the post's content, or stored from it when no
+// row matches, and tracked from then on.
+func eventForMessage(b *app.Bot, client *bot.Client, channelID snowflake.ID, messageID snowflake.ID) (sqlc.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	event, err := b.DB.Queries.GetEventByMessage(ctx, pgtype.Int8{Int64: int64(messageID), Valid: true})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return event, err
 	}
 
-	var eventTime int64
-	timeRegex := regexp.MustCompile(`<t:([^:]+):F>`)
-	timeMatch := timeRegex.FindStringSubmatch(msg)
-	if len(timeMatch) > 1 {
-		parsedTime, err := strconv.ParseInt(timeMatch[1], 10, 64)
-		if err != nil {
-			return "", "", 0, 0, fmt.Errorf("failed to parse event time")
-		}
-		eventTime = parsedTime
-	} else {
-		return "", "", 0, 0, fmt.Errorf("failed to parse event time")
+	msg, err := client.Rest.GetMessage(channelID, messageID)
+	if rest.IsJSONErrorCode(err, rest.JSONErrorCodeUnknownMessage) {
+		return event, pgx.ErrNoRows
+	} else if err != nil {
+		return event, err
+	}
+	match := legacySignupRegex.FindStringSubmatch(msg.Content)
+	if msg.Author.ID != client.ID() || match == nil {
+		return event, pgx.ErrNoRows
+	}
+	eventTime, err := strconv.ParseInt(match[3], 10, 64)
+	if err != nil {
+		return event, pgx.ErrNoRows
+	}
+	hours, err := strconv.ParseInt(match[4], 10, 16)
+	if err != nil {
+		return event, pgx.ErrNoRows
 	}
 
-	var hours int16
-	hoursRegex := regexp.MustCompile(`Hours: (\d+) hours`)
-	hoursMatch := hoursRegex.FindStringSubmatch(msg)
-	if len(hoursMatch) > 1 {
-		parsedHours, err := strconv.ParseInt(hoursMatch[1], 10, 16)
+	event, err = b.DB.Queries.TrackLegacyEvent(ctx, sqlc.TrackLegacyEventParams{
+		Name:      match[2],
+		Time:      eventTime,
+		Type:      sqlc.EventType(match[1]),
+		Hours:     int16(hours),
+		MessageID: pgtype.Int8{Int64: int64(messageID), Valid: true},
+		ChannelID: pgtype.Int8{Int64: int64(channelID), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Posts older than the events table have no row to claim
+		event, err = b.DB.Queries.CreateEvent(ctx, sqlc.CreateEventParams{
+			Name:      match[2],
+			Time:      eventTime,
+			Type:      sqlc.EventType(match[1]),
+			Hours:     int16(hours),
+			MessageID: pgtype.Int8{Int64: int64(messageID), Valid: true},
+			ChannelID: pgtype.Int8{Int64: int64(channelID), Valid: true},
+		})
 		if err != nil {
-			return "", "", 0, 0, fmt.Errorf("failed to parse event hours")
+			// Another interaction on the post may have inserted it first
+			if existing, getErr := b.DB.Queries.GetEventByMessage(ctx, pgtype.Int8{Int64: int64(messageID), Valid: true}); getErr == nil {
+				return existing, nil
+			}
 		}
-		hours = int16(parsedHours)
 	}
-
-	return eventType, name, eventTime, hours, nil
-
+	if err == nil {
+		slog.Info("tracked legacy signup post", slog.Int64("event", event.ID), slog.Any("message", messageID))
+	}
+	return event, err
 }
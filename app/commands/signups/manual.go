@@ -2,195 +2,185 @@ package signups
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	"clockey/app"
 	"clockey/database/sqlc"
 
-	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 var Manual = discord.SlashCommandCreate{
-	Name:        "manual",
-	Description: "Manually assign gardeners to an event",
+	Name:                     "manual",
+	Description:              "Manually assign gardeners to an event",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
 	Options: []discord.ApplicationCommandOption{
 		discord.ApplicationCommandOptionString{
-			Name:        "gardener",
-			Description: "Gardener to work on the event",
-			Required:    true,
-			Choices: []discord.ApplicationCommandOptionChoiceString{
-				{
-					Name:  "N1k",
-					Value: "293360731867316225",
-				},
-				{
-					Name:  "Kit",
-					Value: "204923365205475329",
-				},
-				{
-					Name:  "Pupi",
-					Value: "492549065041510403",
-				},
-				{
-					Name:  "WW",
-					Value: "754724309276164159",
-				},
-				{
-					Name:  "Bonteng",
-					Value: "172360818715918337",
-				},
-				{
-					Name:  "Sam",
-					Value: "332438787588227072",
-				},
-			},
+			Name:         "gardener",
+			Description:  "Gardener to work on the event",
+			Required:     true,
+			Autocomplete: true,
 		},
 	},
 }
 
 func ManualCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		notOnRoster := discord.MessageCreate{
+			Content: data.String("gardener") + " is not on the gardener roster",
+			Flags:   discord.MessageFlagEphemeral,
+		}
+		gardenerID, err := strconv.ParseInt(data.String("gardener"), 10, 64)
+		if err != nil {
+			return e.CreateMessage(notOnRoster)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if gardener, err := b.DB.Queries.GetGardener(ctx, gardenerID); err != nil || !gardener.Active {
+			return e.CreateMessage(notOnRoster)
+		}
+
 		// Show modal to collect event details
-		if err := e.Modal(eventModal); err != nil {
-			slog.Error("DisGo error(failed to send modal)", slog.Any("err", err))
+		return e.Modal(eventModal("/manual/" + data.String("gardener")))
+	}
+}
+
+func ManualModalHandler(b *app.Bot) handler.ModalHandler {
+	return func(m *handler.ModalEvent) error {
+		gardenerID, err := strconv.ParseInt(m.Vars["gardener"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		// Handle the event details submission
+		unixValue, err := strconv.ParseInt(m.Data.Text("event_time"), 0, 64)
+		if err != nil {
+			slog.Error("failed to parse event_time", slog.String("event_time", m.Data.Text("event_time")), slog.Any("err", err))
+			return m.CreateMessage(discord.MessageCreate{
+				Content: m.Data.Text("event_time") + " is not a valid unix time. Please try again.",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		hours, err := strconv.ParseInt(m.Data.Text("event_duration"), 10, 16)
+		if err != nil {
+			slog.Error("failed to parse event_duration", slog.String("event_duration", m.Data.Text("event_duration")), slog.Any("err", err))
+			return m.CreateMessage(discord.MessageCreate{
+				Content: m.Data.Text("event_duration") + " is not a valid number of hours. Please try again.",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		eventType := m.Data.StringValues("event_type")[0]
+		name := m.Data.Text("event_name")
+
+		replyText := "Event: " + eventType + " - " + name + "\n" +
+			"Time: <t:" + m.Data.Text("event_time") + ":F> (<t:" + m.Data.Text("event_time") + ":R>)\n" +
+			"Hours: " + m.Data.Text("event_duration") + " hours\n" +
+			"Gardener: <@" + m.Vars["gardener"] + ">"
+
+		var banner *discord.Icon
+		attachments, provided := m.Data.OptAttachments("event_banner")
+		if provided && len(attachments) > 0 {
+			banner = getBanner(attachments[0])
+		} else {
+			banner = nil
+		}
+
+		if err := m.CreateMessage(discord.MessageCreate{
+			Content: replyText,
+		}); err != nil {
+			slog.Error("DisGo error(failed to send event message)", slog.Any("err", err))
+			return err
+		}
+
+		msg, err := m.Client().Rest.GetInteractionResponse(m.ApplicationID(), m.Token())
+		if err != nil {
+			slog.Error("DisGo error(failed to get interaction response)", slog.Any("err", err))
+			return err
+		}
+
+		var scheduledEventID pgtype.Int8
+		if scheduledEvent, err := createScheduledEvent(m.Client(), *m.GuildID(), b.Cfg.Signups, eventType, name, time.Unix(unixValue, 0), int16(hours), banner); err == nil {
+			scheduledEventID = pgtype.Int8{Int64: int64(scheduledEvent.ID), Valid: true}
+		} else {
+			slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tx, err := b.DB.Conn.Begin(ctx)
+		if err != nil {
+			slog.Error("failed to begin transaction", slog.Any("err", err))
 			return err
 		}
 
-		// Handle modal submission
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-			bot.WaitForEvent(e.Client(), ctx,
-				func(m *events.ModalSubmitInteractionCreate) bool {
-					return m.Data.CustomID == "event_modal"
-				},
-				func(m *events.ModalSubmitInteractionCreate) {
-					// Handle the event details submission
-					unixValue, err := strconv.ParseInt(m.Data.Text("event_time"), 0, 64)
-					if err != nil {
-						slog.Error("failed to parse event_time", slog.String("event_time", m.Data.Text("event_time")), slog.Any("err", err))
-						if err := m.CreateMessage(discord.MessageCreate{
-							Content: m.Data.Text("event_time") + " is not a valid unix time. Please try again.",
-						}); err != nil {
-							slog.Error("DisGo error(failed to send message)", slog.Any("err", err))
-						}
-						return
-					}
-
-					hours, _ := strconv.ParseInt(m.Data.Text("event_duration"), 10, 16)
-					gardener, _ := strconv.ParseInt(data.String("gardener"), 10, 64)
-
-					if err := b.DB.Queries.CreateEvent(ctx, sqlc.CreateEventParams{
-						Type:     sqlc.EventType(m.Data.StringValues("event_type")[0]),
-						Name:     m.Data.Text("event_name"),
-						Time:     unixValue,
-						Hours:    int16(hours),
-						Gardener: gardener,
-					}); err != nil {
-						slog.Error("failed to create event in database", slog.Any("err", err))
-						return
-					}
-
-					replyText := "Event: " + m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name") + "\n" +
-						"Time: <t:" + m.Data.Text("event_time") + ":F> (<t:" + m.Data.Text("event_time") + ":R>)\n" +
-						"Hours: " + m.Data.Text("event_duration") + " hours\n" +
-						"Gardener: <@" + data.String("gardener") + ">"
-
-					var banner *discord.Icon
-					attachments, provided := m.Data.OptAttachments("event_banner")
-					if provided && len(attachments) > 0 {
-						banner = getBanner(attachments[0])
-					} else {
-						banner = nil
-					}
-
-					if err := m.CreateMessage(discord.MessageCreate{
-						Content: replyText,
-					}); err != nil {
-						slog.Error("DisGo error(failed to send event message)", slog.Any("err", err))
-						return
-					}
-
-					switch m.Data.StringValues("event_type")[0] {
-					case "Dota":
-						if _, err := m.Client().Rest.CreateGuildScheduledEvent(*m.GuildID(), discord.GuildScheduledEventCreate{
-							Name:               m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name"),
-							EntityType:         discord.ScheduledEventEntityTypeVoice,
-							PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
-							ChannelID:          channels.VoiceChannels["Dota"],
-							ScheduledStartTime: time.Unix(unixValue, 0),
-							Image:              banner,
-						}); err != nil {
-							slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
-						}
-					case "CS":
-						if _, err := m.Client().Rest.CreateGuildScheduledEvent(*m.GuildID(), discord.GuildScheduledEventCreate{
-							Name:               m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name"),
-							EntityType:         discord.ScheduledEventEntityTypeVoice,
-							PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
-							ChannelID:          channels.VoiceChannels["CS"],
-							ScheduledStartTime: time.Unix(unixValue, 0),
-							Image:              banner,
-						}); err != nil {
-							slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
-						}
-					case "MLBB":
-						if _, err := m.Client().Rest.CreateGuildScheduledEvent(*m.GuildID(), discord.GuildScheduledEventCreate{
-							Name:         m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name"),
-							EntityType:   discord.ScheduledEventEntityTypeExternal,
-							PrivacyLevel: discord.ScheduledEventPrivacyLevelGuildOnly,
-							EntityMetaData: &discord.EntityMetaData{
-								Location: channels.ExternalChannels["MLBB"],
-							},
-							ScheduledStartTime: time.Unix(unixValue, 0),
-							Image:              banner,
-						}); err != nil {
-							slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
-						}
-					case "HoK":
-						if _, err := m.Client().Rest.CreateGuildScheduledEvent(*m.GuildID(), discord.GuildScheduledEventCreate{
-							Name:         m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name"),
-							EntityType:   discord.ScheduledEventEntityTypeExternal,
-							PrivacyLevel: discord.ScheduledEventPrivacyLevelGuildOnly,
-							EntityMetaData: &discord.EntityMetaData{
-								Location: channels.ExternalChannels["HoK"],
-							},
-							ScheduledStartTime: time.Unix(unixValue, 0),
-							Image:              banner,
-						}); err != nil {
-							slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
-						}
-					case "Others":
-						if _, err := m.Client().Rest.CreateGuildScheduledEvent(*m.GuildID(), discord.GuildScheduledEventCreate{
-							Name:               m.Data.StringValues("event_type")[0] + " - " + m.Data.Text("event_name"),
-							EntityType:         discord.ScheduledEventEntityTypeStageInstance,
-							PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
-							ChannelID:          channels.StageChannel,
-							ScheduledStartTime: time.Unix(unixValue, 0),
-							Image:              banner,
-						}); err != nil {
-							slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
-						}
-					default:
-						panic(fmt.Sprintf("invalid state: %s", m.Data.StringValues("event_type")[0]))
-					}
-
-				},
-				func() {
-					if err := e.CreateMessage(discord.MessageCreate{
-						Content: "Modal timed out. Please try again.",
-					}); err != nil {
-						slog.Error("DisGo error(failed to send modal timeout message)", slog.Any("err", err))
-					}
-				},
-			)
+		defer func() {
+			if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+				slog.Error("failed to rollback transaction", slog.Any("err", err))
+			}
 		}()
+
+		event, err := b.DB.Queries.WithTx(tx).CreateEvent(ctx, sqlc.CreateEventParams{
+			Type:             sqlc.EventType(eventType),
+			Name:             name,
+			Time:             unixValue,
+			Hours:            int16(hours),
+			MessageID:        pgtype.Int8{Int64: int64(msg.ID), Valid: true},
+			ChannelID:        pgtype.Int8{Int64: int64(msg.ChannelID), Valid: true},
+			ScheduledEventID: scheduledEventID,
+		})
+		if err != nil {
+			slog.Error("failed to create event in database", slog.Any("err", err))
+			return err
+		}
+		if err := b.DB.Queries.WithTx(tx).AddEventGardener(ctx, sqlc.AddEventGardenerParams{
+			Event:    event.ID,
+			Gardener: gardenerID,
+		}); err != nil {
+			slog.Error("failed to add event gardener to database", slog.Any("err", err))
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			slog.Error("failed to commit transaction", slog.Any("err", err))
+			return err
+		}
 		return nil
 	}
 }
+
+func ManualAutocompleteHandler(b *app.Bot) handler.AutocompleteHandler {
+	return func(e *handler.AutocompleteEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		gardeners, err := b.DB.Queries.ListGardeners(ctx)
+		if err != nil {
+			slog.Error("failed to list gardeners", slog.Any("err", err))
+			return e.AutocompleteResult([]discord.AutocompleteChoice{})
+		}
+
+		query := strings.ToLower(e.Data.Focused().String())
+		choices := []discord.AutocompleteChoice{}
+		for _, gardener := range gardeners {
+			if len(choices) == 25 {
+				break
+			}
+			if strings.Contains(strings.ToLower(gardener.Name), query) {
+				choices = append(choices, discord.AutocompleteChoiceString{
+					Name:  gardener.Name,
+					Value: strconv.FormatInt(gardener.ID, 10),
+				})
+			}
+		}
+		return e.AutocompleteResult(choices)
+	}
+}
@@ -0,0 +1,343 @@
+package signups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/app/liquipedia"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var wikiEventTypes = map[string]sqlc.EventType{
+	liquipedia.Dota2:         sqlc.EventTypeDota,
+	liquipedia.CounterStrike: sqlc.EventTypeCS,
+	liquipedia.MobileLegends: sqlc.EventTypeMLBB,
+	liquipedia.HonorOfKings:  sqlc.EventTypeHoK,
+}
+
+// WatchMatches drafts a signup post for every new OG match on Liquipedia
+// until ctx is cancelled. Drafts are only published once a mod approves them.
+func WatchMatches(ctx context.Context, b *app.Bot) {
+	if b.Cfg.Signups.MatchPollInterval <= 0 || b.Cfg.Signups.DraftChannel == 0 || b.Cfg.Signups.SignupChannel == 0 {
+		slog.Info("Match drafting disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(b.Cfg.Signups.MatchPollInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		for _, wiki := range liquipedia.Wikis {
+			draftMatches(ctx, b, wiki)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func draftMatches(ctx context.Context, b *app.Bot, wiki string) {
+	matches, err := b.Liquipedia.UpcomingMatches(ctx, wiki)
+	if err != nil {
+		slog.Error("failed to get upcoming matches", slog.String("wiki", wiki), slog.Any("err", err))
+		return
+	}
+
+	for _, match := range matches {
+		// Matches without an exact time or a known opponent get drafted on a
+		// later run once Liquipedia has them
+		if match.Dateexact == 0 || match.Date.Before(time.Now()) {
+			continue
+		}
+		us, them, ok := match.Sides(b.Liquipedia.Team(wiki))
+		if !ok || them.Name == "" || strings.EqualFold(them.Name, "TBD") {
+			continue
+		}
+
+		if err := draftMatch(ctx, b, wiki, match, us.Name+" vs "+them.Name); err != nil {
+			slog.Error("failed to draft match", slog.String("wiki", wiki), slog.String("match", match.Match2ID), slog.Any("err", err))
+		}
+	}
+}
+
+// draftMatch posts a match for approval, the row is only kept once the post
+// went through. Pending drafts are edited when the match changes.
+func draftMatch(ctx context.Context, b *app.Bot, wiki string, match liquipedia.ResultElement, name string) error {
+	eventType := wikiEventTypes[wiki]
+	hours := b.Cfg.Signups.MatchHours[string(eventType)]
+	if hours == 0 {
+		// Roughly an hour per game
+		hours = int16(max(match.Bestof, 1))
+	}
+
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	draft, err := b.DB.Queries.WithTx(tx).CreateMatchDraft(ctx, sqlc.CreateMatchDraftParams{
+		Wiki:    wiki,
+		MatchID: match.Match2ID,
+		Type:    eventType,
+		Name:    name,
+		Time:    match.Date.Unix(),
+		Hours:   hours,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	found := "New match found"
+	if draft.MessageID.Valid {
+		found = "Match changed"
+	}
+	content := fmt.Sprintf("%s in %s, approve it to post the signup and create the scheduled event:\n\n%s", found, match.Tournament, draftContent(b, draft))
+	if stream := match.Stream.URL(); stream != "" {
+		content += "\nStream: <" + stream + ">"
+	}
+
+	if draft.MessageID.Valid {
+		_, err := b.Client.Rest.UpdateMessage(snowflake.ID(draft.ChannelID.Int64), snowflake.ID(draft.MessageID.Int64), discord.MessageUpdate{
+			Content: omit.Ptr(content),
+		})
+		if err == nil {
+			return tx.Commit(ctx)
+		} else if !rest.IsJSONErrorCode(err, rest.JSONErrorCodeUnknownMessage) {
+			return fmt.Errorf("failed to update draft: %w", err)
+		}
+		// The draft post was deleted, post it again
+	}
+
+	msg, err := b.Client.Rest.CreateMessage(b.Cfg.Signups.DraftChannel, discord.MessageCreate{
+		Content:         content,
+		Components:      []discord.LayoutComponent{draftButtons(draft)},
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post draft: %w", err)
+	}
+
+	if err := b.DB.Queries.WithTx(tx).SetMatchDraftMessage(ctx, sqlc.SetMatchDraftMessageParams{
+		ID:        draft.ID,
+		MessageID: pgtype.Int8{Int64: int64(msg.ID), Valid: true},
+		ChannelID: pgtype.Int8{Int64: int64(msg.ChannelID), Valid: true},
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func draftContent(b *app.Bot, draft sqlc.MatchDraft) string {
+	return signupContent(b.Cfg.Signups, string(draft.Type), draft.Name, draft.Time, draft.Hours)
+}
+
+func draftButtons(draft sqlc.MatchDraft) discord.ActionRowComponent {
+	return discord.ActionRowComponent{
+		Components: []discord.InteractiveComponent{
+			discord.ButtonComponent{
+				Label:    "Approve",
+				Style:    discord.ButtonStyleSuccess,
+				CustomID: fmt.Sprintf("/draft/%d/approve", draft.ID),
+			},
+			discord.ButtonComponent{
+				Label:    "Reject",
+				Style:    discord.ButtonStyleDanger,
+				CustomID: fmt.Sprintf("/draft/%d/reject", draft.ID),
+			},
+		},
+	}
+}
+
+func DraftComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		draftID, err := strconv.ParseInt(e.Vars["draft"], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		draft, err := b.DB.Queries.GetMatchDraft(ctx, draftID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:    omit.Ptr("This draft no longer exists"),
+				Components: &[]discord.LayoutComponent{},
+			})
+		} else if err != nil {
+			slog.Error("failed to get match draft", slog.Int64("draft", draftID), slog.Any("err", err))
+			return err
+		}
+
+		if e.Vars["action"] != "approve" {
+			rejected, err := b.DB.Queries.SetMatchDraftStatus(ctx, sqlc.SetMatchDraftStatusParams{
+				ID:     draft.ID,
+				Status: "rejected",
+			})
+			if err != nil {
+				slog.Error("failed to reject match draft", slog.Int64("draft", draft.ID), slog.Any("err", err))
+				return e.CreateMessage(discord.MessageCreate{
+					Content: "Error rejecting draft, please try again",
+					Flags:   discord.MessageFlagEphemeral,
+				})
+			}
+			if rejected == 0 {
+				return e.UpdateMessage(discord.MessageUpdate{
+					Content:    omit.Ptr(fmt.Sprintf("%s - %s has already been handled", draft.Type, draft.Name)),
+					Components: &[]discord.LayoutComponent{},
+				})
+			}
+			return e.UpdateMessage(discord.MessageUpdate{
+				Content:         omit.Ptr(fmt.Sprintf("%s - %s rejected by %s", draft.Type, draft.Name, e.User().Mention())),
+				Components:      &[]discord.LayoutComponent{},
+				AllowedMentions: &discord.AllowedMentions{},
+			})
+		}
+
+		if draft.Time < time.Now().Unix() {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This match has already started, create the signup with /event if it is still needed",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		if err := e.DeferUpdateMessage(); err != nil {
+			slog.Error("DisGo error(failed to defer update message)", slog.Any("err", err))
+			return err
+		}
+
+		replyText, err := publishDraft(b, e, draft)
+		if err != nil {
+			slog.Error("failed to publish match draft", slog.Int64("draft", draft.ID), slog.Any("err", err))
+			if _, err := e.CreateFollowupMessage(discord.MessageCreate{
+				Content: "Failed to publish signup, please try again: " + err.Error(),
+				Flags:   discord.MessageFlagEphemeral,
+			}); err != nil {
+				slog.Error("DisGo error(failed to create followup message)", slog.Any("err", err))
+				return err
+			}
+			return nil
+		}
+
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content:         omit.Ptr(draftContent(b, draft) + "\n\n" + replyText),
+			Components:      &[]discord.LayoutComponent{},
+			AllowedMentions: &discord.AllowedMentions{},
+		}); err != nil {
+			slog.Error("DisGo error(failed to update interaction response)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+}
+
+// publishDraft posts the signup of an approved draft. Whatever was posted is
+// taken down if the signup can't be saved, so it can be approved again.
+func publishDraft(b *app.Bot, e *handler.ComponentEvent, draft sqlc.MatchDraft) (replyText string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	approved, err := b.DB.Queries.WithTx(tx).SetMatchDraftStatus(ctx, sqlc.SetMatchDraftStatusParams{
+		ID:     draft.ID,
+		Status: "approved",
+	})
+	if err != nil {
+		return "", err
+	}
+	if approved == 0 {
+		return "This draft has already been handled", nil
+	}
+
+	msg, err := e.Client().Rest.CreateMessage(b.Cfg.Signups.SignupChannel, discord.MessageCreate{
+		Content: draftContent(b, draft),
+		AllowedMentions: &discord.AllowedMentions{
+			Parse: []discord.AllowedMentionType{
+				discord.AllowedMentionTypeRoles,
+				discord.AllowedMentionTypeUsers,
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to post signup: %w", err)
+	}
+
+	if err := e.Client().Rest.AddReaction(msg.ChannelID, msg.ID, b.Cfg.Signups.SignupEmoji); err != nil {
+		slog.Error("DisGo error(failed to add reaction to event message)", slog.Any("err", err))
+	}
+
+	var scheduledEventID pgtype.Int8
+	if scheduledEvent, err := createScheduledEvent(e.Client(), *e.GuildID(), b.Cfg.Signups, string(draft.Type), draft.Name, time.Unix(draft.Time, 0), draft.Hours, nil); err == nil {
+		scheduledEventID = pgtype.Int8{Int64: int64(scheduledEvent.ID), Valid: true}
+	} else {
+		slog.Error("DisGo error(failed to create scheduled event)", slog.Any("err", err))
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := e.Client().Rest.DeleteMessage(msg.ChannelID, msg.ID); err != nil {
+			slog.Error("DisGo error(failed to delete signup message)", slog.Any("message", msg.ID), slog.Any("err", err))
+		}
+		if scheduledEventID.Valid {
+			if err := e.Client().Rest.DeleteGuildScheduledEvent(*e.GuildID(), snowflake.ID(scheduledEventID.Int64)); err != nil {
+				slog.Error("DisGo error(failed to delete scheduled event)", slog.Int64("scheduled_event", scheduledEventID.Int64), slog.Any("err", err))
+			}
+		}
+	}()
+
+	if _, err := b.DB.Queries.WithTx(tx).CreateEvent(ctx, sqlc.CreateEventParams{
+		Type:             draft.Type,
+		Name:             draft.Name,
+		Time:             draft.Time,
+		Hours:            draft.Hours,
+		MessageID:        pgtype.Int8{Int64: int64(msg.ID), Valid: true},
+		ChannelID:        pgtype.Int8{Int64: int64(msg.ChannelID), Valid: true},
+		ScheduledEventID: scheduledEventID,
+	}); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	replyText = "Approved by " + e.User().Mention() + ", signup posted: " + msg.JumpURL()
+	if !scheduledEventID.Valid {
+		replyText += "\nFailed to create the scheduled event, please create it manually"
+	}
+	return replyText, nil
+}
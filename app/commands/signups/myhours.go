@@ -0,0 +1,126 @@
+package signups
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/jackc/pgx/v5"
+)
+
+var MyHours = discord.SlashCommandCreate{
+	Name:        "myhours",
+	Description: "See the events and hours you worked, this month by default",
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionString{
+			Name:        "month",
+			Description: "Month to look at, please use MM-YYYY format",
+			Required:    false,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "start_date",
+			Description: "Start date instead of a month, please use DD-MM-YYYY format",
+			Required:    false,
+		},
+		discord.ApplicationCommandOptionString{
+			Name:        "end_date",
+			Description: "End date when a start date is given, please use DD-MM-YYYY format. Today by default",
+			Required:    false,
+		},
+	},
+}
+
+// MyHoursCommandHandler shows gardeners their own invoice. It only ever
+// reads the events of the user running it.
+func MyHoursCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		startDate, endDate, problem := myHoursPeriod(data)
+		if problem != "" {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: problem,
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		gardener, err := b.DB.Queries.GetGardener(ctx, int64(e.User().ID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "You are not on the gardener roster",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		} else if err != nil {
+			slog.Error("failed to get gardener", slog.Any("user", e.User().ID), slog.Any("err", err))
+			return err
+		}
+
+		events, err := b.DB.Queries.GetEventsForGardener(ctx, sqlc.GetEventsForGardenerParams{
+			Gardener:  gardener.ID,
+			StartTime: startDate.Unix(),
+			EndTime:   endDate.Unix(),
+		})
+		if err != nil {
+			slog.Error("failed to get events for gardener", slog.Int64("gardener", gardener.ID), slog.Any("err", err))
+			return err
+		}
+		rates, err := b.DB.Queries.ListHourlyRates(ctx)
+		if err != nil {
+			slog.Error("failed to list hourly rates", slog.Any("err", err))
+			return err
+		}
+
+		invoice := GardenerReportResult{Gardener: gardener, Events: events}
+		return e.CreateMessage(discord.MessageCreate{
+			Components: invoiceLayout(b.Cfg.Signups, invoice, rates, startDate, endDate),
+			Flags:      discord.MessageFlagEphemeral | discord.MessageFlagIsComponentsV2,
+		})
+	}
+}
+
+// myHoursPeriod returns the period picked with the options, the current month
+// when none are given, or why the options can't be used.
+func myHoursPeriod(data discord.SlashCommandInteractionData) (time.Time, time.Time, string) {
+	monthString, month := data.OptString("month")
+	startString, start := data.OptString("start_date")
+	endString, end := data.OptString("end_date")
+	if month && start {
+		return time.Time{}, time.Time{}, "Pick either a month or a start date, not both"
+	}
+	if end && !start {
+		return time.Time{}, time.Time{}, "An end date needs a start date"
+	}
+
+	if start {
+		startDate, err := time.Parse("02-01-2006", startString)
+		if err != nil {
+			return time.Time{}, time.Time{}, "Failed to parse " + startString + ", please try again"
+		}
+		endDate := time.Now()
+		if end {
+			if endDate, err = parseEndDate(endString); err != nil {
+				return time.Time{}, time.Time{}, "Failed to parse " + endString + ", please try again"
+			}
+		}
+		return startDate, endDate, ""
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if month {
+		var err error
+		if monthStart, err = time.Parse("01-2006", monthString); err != nil {
+			return time.Time{}, time.Time{}, "Failed to parse " + monthString + ", please try again"
+		}
+	}
+	return monthStart, monthStart.AddDate(0, 1, 0).Add(-time.Second), ""
+}
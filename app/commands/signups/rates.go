@@ -0,0 +1,255 @@
+package signups
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var Rate = discord.SlashCommandCreate{
+	Name:                     "rate",
+	Description:              "Manage the hourly rates used in reports",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "set",
+			Description: "Set an hourly rate, for everyone unless a gardener or game is given",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionString{
+					Name:        "amount",
+					Description: "The hourly rate, e.g. 12.50",
+					Required:    true,
+				},
+				discord.ApplicationCommandOptionUser{
+					Name:        "gardener",
+					Description: "Only apply the rate to this gardener",
+					Required:    false,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:        "game",
+					Description: "Only apply the rate to events of this game",
+					Required:    false,
+					Choices: []discord.ApplicationCommandOptionChoiceString{
+						{
+							Name:  "Dota",
+							Value: "Dota",
+						},
+						{
+							Name:  "CS",
+							Value: "CS",
+						},
+						{
+							Name:  "MLBB",
+							Value: "MLBB",
+						},
+						{
+							Name:  "HoK",
+							Value: "HoK",
+						},
+						{
+							Name:  "Other",
+							Value: "Other",
+						},
+					},
+				},
+				discord.ApplicationCommandOptionString{
+					Name:        "effective_from",
+					Description: "First day the rate applies, please use DD-MM-YYYY format. Today by default",
+					Required:    false,
+				},
+			},
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "list",
+			Description: "List the hourly rates",
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "remove",
+			Description: "Remove an hourly rate",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionInt{
+					Name:        "id",
+					Description: "The ID shown in /rate list",
+					Required:    true,
+				},
+			},
+		},
+	},
+}
+
+func RateSetCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		amount := data.String("amount")
+		cents, err := parseCents(amount)
+		if err != nil {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Failed to parse " + amount + ", please use a positive amount like 12.50",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		effectiveFrom := time.Now().UTC().Truncate(24 * time.Hour)
+		if date, ok := data.OptString("effective_from"); ok {
+			effectiveFrom, err = time.Parse("02-01-2006", date)
+			if err != nil {
+				return e.CreateMessage(discord.MessageCreate{
+					Content: "Failed to parse " + date + ", please try again",
+					Flags:   discord.MessageFlagEphemeral,
+				})
+			}
+		}
+
+		params := sqlc.CreateHourlyRateParams{
+			RateCents:     cents,
+			EffectiveFrom: effectiveFrom.Unix(),
+		}
+		if user, ok := data.OptUser("gardener"); ok {
+			params.Gardener = pgtype.Int8{Int64: int64(user.ID), Valid: true}
+		}
+		if game, ok := data.OptString("game"); ok {
+			params.Type = sqlc.NullEventType{EventType: sqlc.EventType(game), Valid: true}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		rate, err := b.DB.Queries.CreateHourlyRate(ctx, params)
+		if err != nil {
+			slog.Error("failed to create hourly rate", slog.Any("err", err))
+			return err
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         "Rate set: " + rateLabel(rate, b.Cfg.Signups),
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+func RateListCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		rates, err := b.DB.Queries.ListHourlyRates(ctx)
+		if err != nil {
+			slog.Error("failed to list hourly rates", slog.Any("err", err))
+			return err
+		}
+
+		content := "No hourly rates set yet"
+		if len(rates) > 0 {
+			content = "# Hourly rates\n"
+			for _, rate := range rates {
+				content += rateLabel(rate, b.Cfg.Signups) + "\n"
+			}
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         content,
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
+
+func RateRemoveCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		id := data.Int("id")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		removed, err := b.DB.Queries.DeleteHourlyRate(ctx, int64(id))
+		if err != nil {
+			slog.Error("failed to delete hourly rate", slog.Int("rate", id), slog.Any("err", err))
+			return err
+		}
+
+		content := fmt.Sprintf("Rate %d removed", id)
+		if removed == 0 {
+			content = fmt.Sprintf("Rate %d does not exist", id)
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content: content,
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
+
+func rateLabel(rate sqlc.HourlyRate, cfg app.SignupsConfig) string {
+	label := fmt.Sprintf("`%d` %s/hour", rate.ID, formatMoney(int64(rate.RateCents), cfg))
+	if rate.Gardener.Valid {
+		label += fmt.Sprintf(" for <@%d>", rate.Gardener.Int64)
+	}
+	if rate.Type.Valid {
+		label += " on " + string(rate.Type.EventType)
+	}
+	if !rate.Gardener.Valid && !rate.Type.Valid {
+		label += " for everyone"
+	}
+	return label + " from " + time.Unix(rate.EffectiveFrom, 0).UTC().Format("02 Jan 2006")
+}
+
+// parseCents parses an amount like 12.5 into cents.
+func parseCents(amount string) (int32, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 || value*100 > math.MaxInt32 {
+		return 0, fmt.Errorf("amount out of range: %s", amount)
+	}
+	return int32(math.Round(value * 100)), nil
+}
+
+func formatMoney(cents int64, cfg app.SignupsConfig) string {
+	return centsString(cents) + " " + currency(cfg)
+}
+
+func centsString(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func currency(cfg app.SignupsConfig) string {
+	return cmp.Or(cfg.Currency, "EUR")
+}
+
+// rateFor returns the most specific rate in cents in effect at the event:
+// gardener and game, then gardener, then game, then everyone. The rates must
+// be ordered by effective_from.
+func rateFor(rates []sqlc.HourlyRate, gardenerID int64, event sqlc.Event) (int32, bool) {
+	best, bestScope := sqlc.HourlyRate{}, -1
+	for _, rate := range rates {
+		if rate.EffectiveFrom > event.Time ||
+			rate.Gardener.Valid && rate.Gardener.Int64 != gardenerID ||
+			rate.Type.Valid && rate.Type.EventType != event.Type {
+			continue
+		}
+
+		scope := 0
+		if rate.Gardener.Valid {
+			scope += 2
+		}
+		if rate.Type.Valid {
+			scope++
+		}
+		if scope >= bestScope {
+			best, bestScope = rate, scope
+		}
+	}
+	return best.RateCents, bestScope != -1
+}
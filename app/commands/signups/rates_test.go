@@ -0,0 +1,103 @@
+package signups
+
+import (
+	"testing"
+
+	"clockey/database/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		amount  string
+		want    int32
+		wantErr bool
+	}{
+		{amount: "12", want: 1200},
+		{amount: " 12.5 ", want: 1250},
+		{amount: "0.015", want: 2},
+		{amount: "0", want: 0},
+		{amount: "-1", wantErr: true},
+		{amount: "NaN", wantErr: true},
+		{amount: "Inf", wantErr: true},
+		{amount: "-Inf", wantErr: true},
+		{amount: "1e10", wantErr: true},
+		{amount: "twelve", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseCents(tc.amount)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseCents(%q) error = %v, want error %t", tc.amount, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && got != tc.want {
+			t.Errorf("parseCents(%q) = %d, want %d", tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestRateFor(t *testing.T) {
+	const gardener, other = 1, 2
+	rate := func(cents int32, gardenerID int64, eventType sqlc.EventType, from int64) sqlc.HourlyRate {
+		return sqlc.HourlyRate{
+			Gardener:      pgtype.Int8{Int64: gardenerID, Valid: gardenerID != 0},
+			Type:          sqlc.NullEventType{EventType: eventType, Valid: eventType != ""},
+			RateCents:     cents,
+			EffectiveFrom: from,
+		}
+	}
+	event := sqlc.Event{Type: sqlc.EventTypeDota, Time: 100}
+
+	tests := map[string]struct {
+		rates []sqlc.HourlyRate
+		want  int32
+		ok    bool
+	}{
+		"no rates": {},
+		"everyone": {
+			rates: []sqlc.HourlyRate{rate(1000, 0, "", 0)},
+			want:  1000, ok: true,
+		},
+		"game over everyone": {
+			rates: []sqlc.HourlyRate{rate(1000, 0, "", 0), rate(1100, 0, sqlc.EventTypeDota, 0)},
+			want:  1100, ok: true,
+		},
+		"gardener over game": {
+			rates: []sqlc.HourlyRate{rate(1200, gardener, "", 0), rate(1100, 0, sqlc.EventTypeDota, 50)},
+			want:  1200, ok: true,
+		},
+		"gardener and game over gardener": {
+			rates: []sqlc.HourlyRate{rate(1300, gardener, sqlc.EventTypeDota, 0), rate(1200, gardener, "", 50)},
+			want:  1300, ok: true,
+		},
+		"latest in effect within a scope": {
+			rates: []sqlc.HourlyRate{rate(1000, 0, "", 0), rate(1500, 0, "", 50), rate(2000, 0, "", 150)},
+			want:  1500, ok: true,
+		},
+		"effective at the event time": {
+			rates: []sqlc.HourlyRate{rate(1000, 0, "", 0), rate(1500, 0, "", 100)},
+			want:  1500, ok: true,
+		},
+		"other gardener and game ignored": {
+			rates: []sqlc.HourlyRate{
+				rate(1000, 0, "", 0),
+				rate(3000, other, "", 0),
+				rate(3100, 0, sqlc.EventTypeCS, 0),
+				rate(3200, gardener, sqlc.EventTypeCS, 0),
+			},
+			want: 1000, ok: true,
+		},
+		"only future rates": {
+			rates: []sqlc.HourlyRate{rate(1000, 0, "", 150)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := rateFor(tc.rates, gardener, event)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("rateFor = %d, %t, want %d, %t", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package signups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// WatchReminders sends the reminders of upcoming events until ctx is cancelled.
+func WatchReminders(ctx context.Context, b *app.Bot) {
+	if b.Cfg.Reminders.Interval <= 0 {
+		slog.Info("Event reminders disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(b.Cfg.Reminders.Interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		sendReminders(ctx, b)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reminders that keep failing, e.g. to a deleted channel, are given up on
+const maxReminderAttempts = 3
+
+func sendReminders(ctx context.Context, b *app.Bot) {
+	events, err := b.DB.Queries.ListUpcomingEvents(ctx, time.Now().Unix())
+	if err != nil {
+		slog.Error("failed to list upcoming events", slog.Any("err", err))
+		return
+	}
+
+	for _, event := range events {
+		gardeners, err := b.DB.Queries.ListEventGardeners(ctx, event.ID)
+		if err != nil {
+			slog.Error("failed to list event gardeners", slog.Int64("event", event.ID), slog.Any("err", err))
+			continue
+		}
+		for _, gardener := range gardeners {
+			send := func() error {
+				return remindGardener(b, event, snowflake.ID(gardener))
+			}
+			if err := remind(ctx, b, event, "gardener", gardener, b.Cfg.Reminders.GardenerOffsets, send); err != nil {
+				slog.Error("failed to remind gardener", slog.Int64("event", event.ID), slog.Int64("gardener", gardener), slog.Any("err", err))
+			}
+		}
+		if b.Cfg.Reminders.Channel != 0 {
+			send := func() error {
+				return remindChannel(b, event)
+			}
+			if err := remind(ctx, b, event, "channel", 0, b.Cfg.Reminders.ChannelOffsets, send); err != nil {
+				slog.Error("failed to post event reminder", slog.Int64("event", event.ID), slog.Any("err", err))
+			}
+		}
+	}
+}
+
+// remind sends a reminder when an offset is due, once for all offsets due at
+// the same time.
+func remind(ctx context.Context, b *app.Bot, event sqlc.Event, kind string, recipient int64, offsets []int, send func() error) error {
+	now := time.Now().Unix()
+	due := slices.DeleteFunc(slices.Clone(offsets), func(offset int) bool {
+		return event.Time-int64(offset) > now
+	})
+	if len(due) == 0 {
+		return nil
+	}
+
+	tx, err := b.DB.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			slog.Error("failed to rollback transaction", slog.Any("err", err))
+		}
+	}()
+
+	var claimed int64
+	for _, offset := range due {
+		rows, err := b.DB.Queries.WithTx(tx).ClaimEventReminder(ctx, sqlc.ClaimEventReminderParams{
+			Event:         event.ID,
+			Kind:          kind,
+			Recipient:     recipient,
+			OffsetSeconds: int32(offset),
+			EventTime:     event.Time,
+			MaxAttempts:   maxReminderAttempts,
+		})
+		if err != nil {
+			return err
+		}
+		claimed += rows
+	}
+	if claimed == 0 {
+		return nil
+	}
+
+	sendErr := send()
+	if sendErr == nil {
+		if err := b.DB.Queries.WithTx(tx).MarkEventRemindersSent(ctx, sqlc.MarkEventRemindersSentParams{
+			Event:     event.ID,
+			Kind:      kind,
+			Recipient: recipient,
+			EventTime: event.Time,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func remindGardener(b *app.Bot, event sqlc.Event, gardenerID snowflake.ID) error {
+	notice := fmt.Sprintf("Reminder: you are working %s - %s <t:%d:R> (<t:%d:F>)", event.Type, event.Name, event.Time, event.Time)
+
+	dm, err := b.Client.Rest.CreateDMChannel(gardenerID)
+	if err == nil {
+		_, err = b.Client.Rest.CreateMessage(dm.ID(), discord.MessageCreate{
+			Content: notice,
+		})
+	}
+	if err == nil || !event.MessageID.Valid {
+		return err
+	}
+
+	// DMs closed, remind them under the signup post instead
+	slog.Warn("failed to DM gardener reminder", slog.Any("gardener", gardenerID), slog.Any("err", err))
+	channelID := snowflake.ID(event.ChannelID.Int64)
+	_, err = b.Client.Rest.CreateMessage(channelID, discord.MessageCreate{
+		Content: discord.UserMention(gardenerID) + " " + notice,
+		MessageReference: &discord.MessageReference{
+			MessageID: omit.Ptr(snowflake.ID(event.MessageID.Int64)),
+			ChannelID: omit.Ptr(channelID),
+		},
+	})
+	return err
+}
+
+func remindChannel(b *app.Bot, event sqlc.Event) error {
+	content := fmt.Sprintf("%s - %s starts <t:%d:R>", event.Type, event.Name, event.Time)
+	if event.ScheduledEventID.Valid {
+		channel, err := b.Client.Rest.GetChannel(b.Cfg.Reminders.Channel)
+		if err != nil {
+			return err
+		}
+		if guildChannel, ok := channel.(discord.GuildChannel); ok {
+			content += fmt.Sprintf("\nhttps://discord.com/events/%s/%d", guildChannel.GuildID(), event.ScheduledEventID.Int64)
+		}
+	}
+
+	_, err := b.Client.Rest.CreateMessage(b.Cfg.Reminders.Channel, discord.MessageCreate{
+		Content: content,
+	})
+	return err
+}
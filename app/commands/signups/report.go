@@ -2,25 +2,26 @@ package signups
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
 	"clockey/app"
 	"clockey/database/sqlc"
 
-	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/omit"
 )
 
 var Report = discord.SlashCommandCreate{
-	Name:        "report",
-	Description: "Look at the report of gardener signups over a period of time",
+	Name:                     "report",
+	Description:              "Look at the report of gardener signups over a period of time",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
 	Options: []discord.ApplicationCommandOption{
 		discord.ApplicationCommandOptionString{
 			Name:        "start_date",
@@ -44,7 +45,12 @@ var Report = discord.SlashCommandCreate{
 		},
 		discord.ApplicationCommandOptionString{
 			Name:        "end_date",
-			Description: "End date of the report, please use DD-MM-YYYY format",
+			Description: "Last day of the report, please use DD-MM-YYYY format. Today by default",
+			Required:    false,
+		},
+		discord.ApplicationCommandOptionBool{
+			Name:        "export",
+			Description: "Attach a CSV of the events and a PDF invoice per gardener",
 			Required:    false,
 		},
 	},
@@ -70,7 +76,7 @@ func ReportCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 
 		var endDate time.Time
 		if endDateString, provided := data.OptString("end_date"); provided {
-			endDate, err = time.Parse("02-01-2006", endDateString)
+			endDate, err = parseEndDate(endDateString)
 			if err != nil {
 				if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
 					Content: omit.Ptr("Failed to parse " + endDateString + ", please try again"),
@@ -84,11 +90,14 @@ func ReportCommandHandler(b *app.Bot) handler.SlashCommandHandler {
 		}
 
 		if data.String("report_option") == "gardener" {
-			return GenerateGardenerReport(b, e, startDate, endDate)
+			err = GenerateGardenerReport(b, e, startDate, endDate)
 		} else {
-			return GenerateGameReport(b, e, startDate, endDate)
+			err = GenerateGameReport(b, e, startDate, endDate)
 		}
-
+		if err != nil || !data.Bool("export") {
+			return err
+		}
+		return sendReportExport(b, e, startDate, endDate)
 	}
 }
 
@@ -119,6 +128,26 @@ func GenerateGameReport(b *app.Bot, e *handler.CommandEvent, startDate time.Time
 	wg.Wait()
 	close(invoices)
 
+	// Costs depend on who worked each event, so they come from the gardener reports
+	reported, err := gardenerReports(b, startDate, endDate)
+	if err != nil {
+		slog.Error("failed to list gardeners", slog.Any("err", err))
+		return err
+	}
+	rates, err := listRates(b)
+	if err != nil {
+		slog.Error("failed to list hourly rates", slog.Any("err", err))
+		return err
+	}
+	costs, unrated := map[sqlc.EventType]int64{}, 0
+	for _, invoice := range reported {
+		invoiceCosts, invoiceUnrated := gardenerCosts(rates, invoice)
+		for game, cost := range invoiceCosts {
+			costs[game] += cost
+		}
+		unrated += invoiceUnrated
+	}
+
 	totalHours := 0
 	events := map[string]string{
 		"Dota":  "# Dota\n",
@@ -134,6 +163,11 @@ func GenerateGameReport(b *app.Bot, e *handler.CommandEvent, startDate time.Time
 			totalHours += int(event.Hours)
 		}
 	}
+	var totalCost int64
+	for game, cost := range costs {
+		events[string(game)] += fmt.Sprintf("**Subtotal: %s**", formatMoney(cost, b.Cfg.Signups))
+		totalCost += cost
+	}
 
 	layout := []discord.LayoutComponent{
 		discord.TextDisplayComponent{
@@ -162,7 +196,7 @@ func GenerateGameReport(b *app.Bot, e *handler.CommandEvent, startDate time.Time
 				},
 				discord.SeparatorComponent{},
 				discord.TextDisplayComponent{
-					Content: fmt.Sprintf("**Total: %d**", totalHours),
+					Content: fmt.Sprintf("**Total: %d hours - %s**", totalHours, formatMoney(totalCost, b.Cfg.Signups)) + unratedNote(unrated),
 				},
 			},
 		},
@@ -180,211 +214,236 @@ func GenerateGameReport(b *app.Bot, e *handler.CommandEvent, startDate time.Time
 }
 
 type GardenerReportResult struct {
-	Gardener string
+	Gardener sqlc.Gardener
 	Events   []sqlc.Event
 }
 
 func GenerateGardenerReport(b *app.Bot, e *handler.CommandEvent, startDate time.Time, endDate time.Time) error {
+	reported, err := gardenerReports(b, startDate, endDate)
+	if err != nil {
+		slog.Error("failed to list gardeners", slog.Any("err", err))
+		return err
+	}
+
+	if len(reported) == 0 {
+		if _, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: omit.Ptr("No gardeners on the roster yet"),
+		}); err != nil {
+			slog.Error("DisGo error(failed to send invoice message)", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+
+	rates, err := listRates(b)
+	if err != nil {
+		slog.Error("failed to list hourly rates", slog.Any("err", err))
+		return err
+	}
+
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Components: omit.Ptr(gardenerInvoiceLayout(b.Cfg.Signups, reported, reported[0], rates, startDate, endDate)),
+		Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
+	})
+	if err != nil {
+		slog.Error("failed to send invoice message", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// ReportComponentHandler switches the gardener report to another gardener.
+func ReportComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		gardenerID, err := strconv.ParseInt(e.Vars["gardenerID"], 10, 64)
+		if err != nil {
+			return err
+		}
+		start, err := strconv.ParseInt(e.Vars["start"], 10, 64)
+		if err != nil {
+			return err
+		}
+		end, err := strconv.ParseInt(e.Vars["end"], 10, 64)
+		if err != nil {
+			return err
+		}
+		startDate, endDate := time.Unix(start, 0), time.Unix(end, 0)
+
+		reported, err := gardenerReports(b, startDate, endDate)
+		if err != nil {
+			slog.Error("failed to list gardeners", slog.Any("err", err))
+			return err
+		}
+
+		idx := slices.IndexFunc(reported, func(invoice GardenerReportResult) bool {
+			return invoice.Gardener.ID == gardenerID
+		})
+		if idx == -1 {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "This gardener is no longer part of the report",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		rates, err := listRates(b)
+		if err != nil {
+			slog.Error("failed to list hourly rates", slog.Any("err", err))
+			return err
+		}
+
+		if err := e.UpdateMessage(discord.MessageUpdate{
+			Components: omit.Ptr(gardenerInvoiceLayout(b.Cfg.Signups, reported, reported[idx], rates, startDate, endDate)),
+			Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
+		}); err != nil {
+			slog.Error("DisGo error(failed to update invoice message)", slog.Int64("gardener", gardenerID), slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+}
+
+// gardenerReports fetches the events of every gardener in the period.
+// Removed gardeners only show up when they worked during the period.
+func gardenerReports(b *app.Bot, startDate time.Time, endDate time.Time) ([]GardenerReportResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	gardeners, err := b.DB.Queries.ListAllGardeners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var wg sync.WaitGroup
 
-	invoices := make(chan GardenerReportResult, 6)
-	for id, name := range gardenerIDsMap {
+	invoices := make(chan GardenerReportResult, len(gardeners))
+	errs := make(chan error, len(gardeners))
+	for _, gardener := range gardeners {
 		wg.Go(func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 			if events, err := b.DB.Queries.GetEventsForGardener(ctx, sqlc.GetEventsForGardenerParams{
 				StartTime: startDate.Unix(),
 				EndTime:   endDate.Unix(),
-				Gardener:  int64(id),
+				Gardener:  gardener.ID,
 			}); err == nil {
-				invoices <- GardenerReportResult{Gardener: name, Events: events}
+				invoices <- GardenerReportResult{Gardener: gardener, Events: events}
 			} else {
-				slog.Error("Failed to get invoice ", slog.Any("name", name))
+				errs <- fmt.Errorf("failed to get events of %s: %w", gardener.Name, err)
 			}
-			// events := test.GetTestEventsForGardener(int64(id))
-			// invoices <- Invoice{Gardener: name, Events: events}
 		})
 	}
 	wg.Wait()
 	close(invoices)
+	close(errs)
 
-	layouts := make(map[string][]discord.LayoutComponent, 6)
+	// A report missing a gardener would under-report hours and costs
+	var failed []error
+	for err := range errs {
+		failed = append(failed, err)
+	}
+	if err := errors.Join(failed...); err != nil {
+		return nil, err
+	}
+
+	results := make(map[int64]GardenerReportResult, len(gardeners))
 	for invoice := range invoices {
-		var dotaEvents, csEvents, mlbbEvents, hokEvents, otherEvents string
-		gardenerHours := 0
-		for _, event := range invoice.Events {
-			schedule := time.Unix(event.Time, 0).Format("02 Jan 2006")
-			switch event.Type {
-			case sqlc.EventTypeDota:
-				dotaEvents += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
-			case sqlc.EventTypeCS:
-				csEvents += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
-			case sqlc.EventTypeMLBB:
-				mlbbEvents += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
-			case sqlc.EventTypeHoK:
-				hokEvents += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
-			case sqlc.EventTypeOther:
-				otherEvents += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
-			}
-			gardenerHours += int(event.Hours)
-		}
+		results[invoice.Gardener.ID] = invoice
+	}
 
-		layouts[invoice.Gardener] = []discord.LayoutComponent{
-			discord.TextDisplayComponent{
-				Content: fmt.Sprintf("# %s's Invoice\n**%s - %s**", invoice.Gardener, startDate.Month().String(), endDate.Month().String()),
-			},
-			discord.ContainerComponent{
-				Components: []discord.ContainerSubComponent{
-					discord.TextDisplayComponent{
-						Content: "# Dota\n" + dotaEvents,
-					},
-					discord.SeparatorComponent{},
-					discord.TextDisplayComponent{
-						Content: "# CS\n" + csEvents,
-					},
-					discord.SeparatorComponent{},
-					discord.TextDisplayComponent{
-						Content: "# MLBB\n" + mlbbEvents,
-					},
-					discord.SeparatorComponent{},
-					discord.TextDisplayComponent{
-						Content: "# HoK\n" + hokEvents,
-					},
-					discord.SeparatorComponent{},
-					discord.TextDisplayComponent{
-						Content: "# Other\n" + otherEvents,
-					},
-					discord.SeparatorComponent{},
-					discord.TextDisplayComponent{
-						Content: fmt.Sprintf("**Total: %d**", gardenerHours),
-					},
-				},
-			},
-			discord.ActionRowComponent{
-				Components: []discord.InteractiveComponent{
-					discord.ButtonComponent{
-						Label:    "N1k",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "n1k_button",
-						Disabled: strings.Contains(invoice.Gardener, "N1k"),
-					},
-					discord.ButtonComponent{
-						Label:    "Kit",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "kit_button",
-						Disabled: strings.Contains(invoice.Gardener, "Kit"),
-					},
-					discord.ButtonComponent{
-						Label:    "Pupi",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "pupi_button",
-						Disabled: strings.Contains(invoice.Gardener, "Pupi"),
-					},
-					discord.ButtonComponent{
-						Label:    "WW",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "ww_button",
-						Disabled: strings.Contains(invoice.Gardener, "WW"),
-					},
-					discord.ButtonComponent{
-						Label:    "Bonteng",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "bonteng_button",
-						Disabled: strings.Contains(invoice.Gardener, "Bonteng"),
-					},
-				},
-			},
-			discord.ActionRowComponent{
-				Components: []discord.InteractiveComponent{
-					discord.ButtonComponent{
-						Label:    "Sam",
-						Style:    discord.ButtonStyleSecondary,
-						CustomID: "sam_button",
-						Disabled: strings.Contains(invoice.Gardener, "Sam"),
-					},
-				},
-			},
+	var reported []GardenerReportResult
+	for _, gardener := range gardeners {
+		if invoice, ok := results[gardener.ID]; ok && (gardener.Active || len(invoice.Events) > 0) {
+			reported = append(reported, invoice)
 		}
+	}
+	return reported, nil
+}
+
+func gardenerInvoiceLayout(cfg app.SignupsConfig, reported []GardenerReportResult, invoice GardenerReportResult, rates []sqlc.HourlyRate, startDate time.Time, endDate time.Time) []discord.LayoutComponent {
+	return append(invoiceLayout(cfg, invoice, rates, startDate, endDate), gardenerButtons(reported, invoice.Gardener.ID, startDate, endDate)...)
+}
 
+// invoiceLayout lists the events of one gardener per game with their hours
+// and earnings.
+func invoiceLayout(cfg app.SignupsConfig, invoice GardenerReportResult, rates []sqlc.HourlyRate, startDate time.Time, endDate time.Time) []discord.LayoutComponent {
+	events := map[sqlc.EventType]string{}
+	hours := map[sqlc.EventType]int{}
+	gardenerHours := 0
+	for _, event := range invoice.Events {
+		schedule := time.Unix(event.Time, 0).Format("02 Jan 2006")
+		events[event.Type] += fmt.Sprintf("%s at %s - %d hours\n", event.Name, schedule, event.Hours)
+		hours[event.Type] += int(event.Hours)
+		gardenerHours += int(event.Hours)
 	}
+	costs, unrated := gardenerCosts(rates, invoice)
 
-	_, err := e.UpdateInteractionResponse(discord.MessageUpdate{
-		Components: omit.Ptr(layouts["N1k"]),
-		Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-	})
-	if err != nil {
-		slog.Error("failed to send invoice message", slog.Any("err", err))
-		return err
+	var components []discord.ContainerSubComponent
+	var totalCost int64
+	for _, game := range []sqlc.EventType{sqlc.EventTypeDota, sqlc.EventTypeCS, sqlc.EventTypeMLBB, sqlc.EventTypeHoK, sqlc.EventTypeOther} {
+		content := "# " + string(game) + "\n" + events[game]
+		if hours[game] > 0 {
+			content += fmt.Sprintf("**Subtotal: %d hours - %s**", hours[game], formatMoney(costs[game], cfg))
+		}
+		components = append(components, discord.TextDisplayComponent{Content: content}, discord.SeparatorComponent{})
+		totalCost += costs[game]
 	}
+	components = append(components, discord.TextDisplayComponent{
+		Content: fmt.Sprintf("**Total: %d hours - %s**", gardenerHours, formatMoney(totalCost, cfg)) + unratedNote(unrated),
+	})
 
-	go func() {
-		ch, cls := bot.NewEventCollector(e.Client(),
-			func(c *events.ComponentInteractionCreate) bool {
-				return c.Data.CustomID() == "n1k_button" ||
-					c.Data.CustomID() == "kit_button" ||
-					c.Data.CustomID() == "pupi_button" ||
-					c.Data.CustomID() == "ww_button" ||
-					c.Data.CustomID() == "bonteng_button" ||
-					c.Data.CustomID() == "sam_button"
-			})
-		defer cls()
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-		defer cancel()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case c := <-ch:
-				switch c.Data.CustomID() {
-				case "n1k_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["N1k"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to N1k)", slog.Any("err", err))
-					}
-				case "kit_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["Kit"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to Kit)", slog.Any("err", err))
-					}
-				case "pupi_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["Pupi"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to Pupi)", slog.Any("err", err))
-					}
-				case "ww_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["WW"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to WW)", slog.Any("err", err))
-					}
-				case "bonteng_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["Bonteng"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to Bonteng)", slog.Any("err", err))
-					}
-				case "sam_button":
-					if err := c.UpdateMessage(discord.MessageUpdate{
-						Components: omit.Ptr(layouts["Sam"]),
-						Flags:      omit.Ptr(discord.MessageFlagIsComponentsV2),
-					}); err != nil {
-						slog.Error("DisGo error(failed to update message to Sam)", slog.Any("err", err))
-					}
-				default:
-					continue
-				}
+	return []discord.LayoutComponent{
+		discord.TextDisplayComponent{
+			Content: fmt.Sprintf("# %s's Invoice\n**%s - %s**", invoice.Gardener.Name, startDate.Month().String(), endDate.Month().String()),
+		},
+		discord.ContainerComponent{
+			Components: components,
+		},
+	}
+}
 
-			}
+// gardenerCosts returns what the gardener earned per game, and how many of
+// their events have no rate to price them.
+func gardenerCosts(rates []sqlc.HourlyRate, invoice GardenerReportResult) (map[sqlc.EventType]int64, int) {
+	costs, unrated := map[sqlc.EventType]int64{}, 0
+	for _, event := range invoice.Events {
+		rate, ok := rateFor(rates, invoice.Gardener.ID, event)
+		if !ok {
+			unrated++
+			continue
 		}
-	}()
+		costs[event.Type] += int64(rate) * int64(event.Hours)
+	}
+	return costs, unrated
+}
 
-	return nil
+func unratedNote(unrated int) string {
+	if unrated == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n%d events have no hourly rate yet", unrated)
+}
+
+func listRates(b *app.Bot) ([]sqlc.HourlyRate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return b.DB.Queries.ListHourlyRates(ctx)
+}
+
+// gardenerButtons builds one button per reported gardener, five to a row,
+// with the currently shown gardener disabled.
+func gardenerButtons(reported []GardenerReportResult, current int64, startDate time.Time, endDate time.Time) []discord.LayoutComponent {
+	var rows []discord.LayoutComponent
+	for chunk := range slices.Chunk(reported, 5) {
+		row := discord.ActionRowComponent{}
+		for _, invoice := range chunk {
+			row.Components = append(row.Components, discord.ButtonComponent{
+				Label:    invoice.Gardener.Name,
+				Style:    discord.ButtonStyleSecondary,
+				CustomID: fmt.Sprintf("/report/%d/%d/%d", invoice.Gardener.ID, startDate.Unix(), endDate.Unix()),
+				Disabled: invoice.Gardener.ID == current,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
 }
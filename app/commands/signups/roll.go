@@ -0,0 +1,167 @@
+package signups
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	rollPolicyRandom     = "random"
+	rollPolicyLeastHours = "least-hours"
+	rollPolicyRoundRobin = "round-robin"
+)
+
+// rollCandidate is a signed up gardener with their workload.
+type rollCandidate struct {
+	Gardener  sqlc.Gardener
+	Hours     int64
+	LastEvent int64
+}
+
+func rollPolicy(b *app.Bot) string {
+	switch b.Cfg.Signups.RollPolicy {
+	case rollPolicyRandom, rollPolicyRoundRobin:
+		return b.Cfg.Signups.RollPolicy
+	default:
+		return rollPolicyLeastHours
+	}
+}
+
+// RollComponentHandler picks the gardener for an event from everyone who
+// signed up, using the configured policy, and shows how they compare.
+func RollComponentHandler(b *app.Bot) handler.ButtonComponentHandler {
+	return func(data discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+		messageID, err := snowflake.Parse(e.Vars["messageID"])
+		if err != nil {
+			return err
+		}
+
+		event, ok, err := unassignedEvent(b, e, messageID)
+		if err != nil || !ok {
+			return err
+		}
+
+		gardeners, err := signedUpGardeners(b, e.Client(), snowflake.ID(event.ChannelID.Int64), messageID)
+		if err != nil {
+			slog.Error("failed to get signed up gardeners", slog.Any("message", messageID), slog.Any("err", err))
+			return err
+		}
+		if len(gardeners) == 0 {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Nobody has signed up for this event yet",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+
+		candidates, err := rollCandidates(b, gardeners)
+		if err != nil {
+			slog.Error("failed to get gardener workload", slog.Any("err", err))
+			return err
+		}
+
+		policy := rollPolicy(b)
+		picked, ok := pickGardener(policy, candidates)
+		if !ok {
+			return e.CreateMessage(discord.MessageCreate{
+				Content: "Nobody has signed up for this event yet",
+				Flags:   discord.MessageFlagEphemeral,
+			})
+		}
+		content := fmt.Sprintf("Rolled <@%d> (%s)\n\nHours this month:\n", picked.Gardener.ID, policy)
+		for _, candidate := range candidates {
+			content += fmt.Sprintf("- %s: %d hours", candidate.Gardener.Name, candidate.Hours)
+			if policy == rollPolicyRoundRobin {
+				if candidate.LastEvent == 0 {
+					content += ", never assigned"
+				} else {
+					content += fmt.Sprintf(", last assigned <t:%d:R>", candidate.LastEvent)
+				}
+			}
+			content += "\n"
+		}
+		content += "\nHours added to the database"
+
+		return assignGardeners(b, e, event, []int64{picked.Gardener.ID}, content)
+	}
+}
+
+// rollCandidates looks up the hours each gardener worked this month and the
+// latest event they were assigned.
+func rollCandidates(b *app.Bot, gardeners []sqlc.Gardener) ([]rollCandidate, error) {
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var candidates []rollCandidate
+	for _, gardener := range gardeners {
+		events, err := b.DB.Queries.GetEventsForGardener(ctx, sqlc.GetEventsForGardenerParams{
+			Gardener:  gardener.ID,
+			StartTime: monthStart.Unix(),
+			EndTime:   monthEnd.Unix(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		lastEvent, err := b.DB.Queries.GetLastEventTimeForGardener(ctx, gardener.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		candidate := rollCandidate{Gardener: gardener, LastEvent: lastEvent}
+		for _, event := range events {
+			candidate.Hours += int64(event.Hours)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// pickGardener applies the policy to the candidates, which are in signup
+// order so ties go to whoever signed up first. It reports false when there
+// are no candidates.
+func pickGardener(policy string, candidates []rollCandidate) (rollCandidate, bool) {
+	if len(candidates) == 0 {
+		return rollCandidate{}, false
+	}
+
+	switch policy {
+	case rollPolicyRandom:
+		// Weighted so a gardener with fewer hours is more likely to be picked
+		weights := make([]float64, len(candidates))
+		total := 0.0
+		for i, candidate := range candidates {
+			weights[i] = 1 / float64(candidate.Hours+1)
+			total += weights[i]
+		}
+		roll := rand.Float64() * total
+		for i, weight := range weights {
+			if roll < weight {
+				return candidates[i], true
+			}
+			roll -= weight
+		}
+		return candidates[len(candidates)-1], true
+	case rollPolicyRoundRobin:
+		return slices.MinFunc(candidates, func(a, b rollCandidate) int {
+			return cmp.Compare(a.LastEvent, b.LastEvent)
+		}), true
+	default:
+		return slices.MinFunc(candidates, func(a, b rollCandidate) int {
+			return cmp.Compare(a.Hours, b.Hours)
+		}), true
+	}
+}
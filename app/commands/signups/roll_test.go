@@ -0,0 +1,103 @@
+package signups
+
+import (
+	"testing"
+
+	"clockey/database/sqlc"
+)
+
+func TestPickGardener(t *testing.T) {
+	candidate := func(id int64, hours int64, lastEvent int64) rollCandidate {
+		return rollCandidate{
+			Gardener:  sqlc.Gardener{ID: id},
+			Hours:     hours,
+			LastEvent: lastEvent,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		candidates []rollCandidate
+		want       int64
+		wantOK     bool
+	}{
+		{
+			name:       "least hours",
+			policy:     rollPolicyLeastHours,
+			candidates: []rollCandidate{candidate(1, 8, 0), candidate(2, 3, 0), candidate(3, 5, 0)},
+			want:       2,
+			wantOK:     true,
+		},
+		{
+			name:       "least hours tie goes to first signup",
+			policy:     rollPolicyLeastHours,
+			candidates: []rollCandidate{candidate(1, 8, 0), candidate(2, 3, 0), candidate(3, 3, 0)},
+			want:       2,
+			wantOK:     true,
+		},
+		{
+			name:       "unknown policy falls back to least hours",
+			policy:     "",
+			candidates: []rollCandidate{candidate(1, 8, 0), candidate(2, 3, 0)},
+			want:       2,
+			wantOK:     true,
+		},
+		{
+			name:       "round robin",
+			policy:     rollPolicyRoundRobin,
+			candidates: []rollCandidate{candidate(1, 0, 300), candidate(2, 9, 100), candidate(3, 0, 200)},
+			want:       2,
+			wantOK:     true,
+		},
+		{
+			name:       "round robin prefers never assigned",
+			policy:     rollPolicyRoundRobin,
+			candidates: []rollCandidate{candidate(1, 0, 300), candidate(2, 0, 0), candidate(3, 0, 0)},
+			want:       2,
+			wantOK:     true,
+		},
+		{
+			name:       "random with one candidate",
+			policy:     rollPolicyRandom,
+			candidates: []rollCandidate{candidate(1, 40, 0)},
+			want:       1,
+			wantOK:     true,
+		},
+		{name: "least hours without candidates", policy: rollPolicyLeastHours},
+		{name: "round robin without candidates", policy: rollPolicyRoundRobin},
+		{name: "random without candidates", policy: rollPolicyRandom},
+	}
+	for _, tc := range tests {
+		got, ok := pickGardener(tc.policy, tc.candidates)
+		if ok != tc.wantOK {
+			t.Errorf("%s: pickGardener() ok = %t, want %t", tc.name, ok, tc.wantOK)
+			continue
+		}
+		if ok && got.Gardener.ID != tc.want {
+			t.Errorf("%s: pickGardener() = gardener %d, want %d", tc.name, got.Gardener.ID, tc.want)
+		}
+	}
+}
+
+func TestPickGardenerRandomWeighting(t *testing.T) {
+	candidates := []rollCandidate{
+		{Gardener: sqlc.Gardener{ID: 1}, Hours: 99},
+		{Gardener: sqlc.Gardener{ID: 2}, Hours: 0},
+	}
+	picks := make(map[int64]int)
+	for range 1000 {
+		got, ok := pickGardener(rollPolicyRandom, candidates)
+		if !ok {
+			t.Fatal("pickGardener() ok = false, want true")
+		}
+		picks[got.Gardener.ID]++
+	}
+	if len(picks) > 2 {
+		t.Fatalf("pickGardener() picked gardeners %v, want only candidates", picks)
+	}
+	// Weights are 1/100 and 1, so the idle gardener is picked ~99% of the time
+	if picks[2] < 900 {
+		t.Errorf("pickGardener() picked the idle gardener %d of 1000 times, want at least 900", picks[2])
+	}
+}
@@ -0,0 +1,171 @@
+package signups
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"clockey/app"
+	"clockey/database/sqlc"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
+)
+
+var Roster = discord.SlashCommandCreate{
+	Name:                     "gardener",
+	Description:              "Manage the gardener roster",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
+	Contexts: []discord.InteractionContextType{
+		discord.InteractionContextTypeGuild,
+	},
+	Options: []discord.ApplicationCommandOption{
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "add",
+			Description: "Add a gardener to the roster",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionUser{
+					Name:        "user",
+					Description: "The gardener to add",
+					Required:    true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:        "name",
+					Description: "The name shown in menus and reports",
+					Required:    true,
+				},
+			},
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "remove",
+			Description: "Remove a gardener from the roster",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionUser{
+					Name:        "user",
+					Description: "The gardener to remove",
+					Required:    true,
+				},
+			},
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "rename",
+			Description: "Change the name shown for a gardener",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionUser{
+					Name:        "user",
+					Description: "The gardener to rename",
+					Required:    true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:        "name",
+					Description: "The new name",
+					Required:    true,
+				},
+			},
+		},
+		discord.ApplicationCommandOptionSubCommand{
+			Name:        "list",
+			Description: "List the current gardeners",
+		},
+	},
+}
+
+func RosterAddCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		user := data.User("user")
+		name := strings.TrimSpace(data.String("name"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := b.DB.Queries.CreateGardener(ctx, sqlc.CreateGardenerParams{
+			ID:   int64(user.ID),
+			Name: name,
+		}); err != nil {
+			slog.Error("failed to create gardener", slog.Any("user", user.ID), slog.Any("err", err))
+			return err
+		}
+
+		return e.CreateMessage(discord.MessageCreate{
+			Content: fmt.Sprintf("%s added to the roster as %s", user.Mention(), name),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
+
+func RosterRemoveCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		user := data.User("user")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		removed, err := b.DB.Queries.RemoveGardener(ctx, int64(user.ID))
+		if err != nil {
+			slog.Error("failed to remove gardener", slog.Any("user", user.ID), slog.Any("err", err))
+			return err
+		}
+
+		content := fmt.Sprintf("%s removed from the roster", user.Mention())
+		if removed == 0 {
+			content = fmt.Sprintf("%s is not on the roster", user.Mention())
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content: content,
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
+
+func RosterRenameCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		user := data.User("user")
+		name := strings.TrimSpace(data.String("name"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		renamed, err := b.DB.Queries.RenameGardener(ctx, sqlc.RenameGardenerParams{
+			ID:   int64(user.ID),
+			Name: name,
+		})
+		if err != nil {
+			slog.Error("failed to rename gardener", slog.Any("user", user.ID), slog.Any("err", err))
+			return err
+		}
+
+		content := fmt.Sprintf("%s renamed to %s", user.Mention(), name)
+		if renamed == 0 {
+			content = fmt.Sprintf("%s is not on the roster", user.Mention())
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content: content,
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+}
+
+func RosterListCommandHandler(b *app.Bot) handler.SlashCommandHandler {
+	return func(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		gardeners, err := b.DB.Queries.ListGardeners(ctx)
+		if err != nil {
+			slog.Error("failed to list gardeners", slog.Any("err", err))
+			return err
+		}
+
+		content := "No gardeners on the roster yet"
+		if len(gardeners) > 0 {
+			content = "# Gardeners\n"
+			for _, gardener := range gardeners {
+				content += fmt.Sprintf("%s - <@%d>\n", gardener.Name, gardener.ID)
+			}
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content:         content,
+			Flags:           discord.MessageFlagEphemeral,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+	}
+}
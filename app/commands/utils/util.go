@@ -9,11 +9,13 @@ import (
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/omit"
 )
 
 var Util = discord.SlashCommandCreate{
-	Name:        "util",
-	Description: "Command that can be reused for multiple purposes, don't use",
+	Name:                     "util",
+	Description:              "Command that can be reused for multiple purposes, don't use",
+	DefaultMemberPermissions: omit.NewPtr(discord.PermissionAdministrator),
 }
 
 func UtilCommandHandler() handler.SlashCommandHandler {
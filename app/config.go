@@ -1,10 +1,13 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 
+	"clockey/app/liquipedia"
+
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/pelletier/go-toml/v2"
 )
@@ -19,13 +22,26 @@ func LoadConfig(path string) (*Config, error) {
 	if err = toml.NewDecoder(file).Decode(&cfg); err != nil {
 		return nil, err
 	}
+	// The mod-log card is where a lockdown is reviewed and ended
+	if cfg.Raid.Joins > 0 && cfg.Moderation.ModLogChannel == 0 {
+		return nil, errors.New("raid.joins requires moderation.mod_log_channel")
+	}
 	return &cfg, nil
 }
 
 type Config struct {
-	Log      LogConfig      `toml:"log"`
-	Bot      BotConfig      `toml:"bot"`
-	Database DatabaseConfig `toml:"database"`
+	Log         LogConfig         `toml:"log"`
+	Bot         BotConfig         `toml:"bot"`
+	Database    DatabaseConfig    `toml:"database"`
+	Signups     SignupsConfig     `toml:"signups"`
+	Reminders   RemindersConfig   `toml:"reminders"`
+	Predictions PredictionsConfig `toml:"predictions"`
+	Moderation  ModerationConfig  `toml:"moderation"`
+	Honeypot    HoneypotConfig    `toml:"honeypot"`
+	Spam        SpamConfig        `toml:"spam"`
+	Raid        RaidConfig        `toml:"raid"`
+	Permissions PermissionsConfig `toml:"permissions"`
+	Liquipedia  liquipedia.Config `toml:"liquipedia"`
 }
 
 type BotConfig struct {
@@ -42,4 +58,72 @@ type LogConfig struct {
 type DatabaseConfig struct {
 	ConnectionString string `toml:"connection_string"`
 	SqlcToken        string `toml:"sqlc_token"`
+	AutoMigrate      bool   `toml:"auto_migrate"`
+}
+
+type SignupsConfig struct {
+	GardenerRoleID    snowflake.ID            `toml:"gardener_role_id"`
+	SignupEmoji       string                  `toml:"signup_emoji"`
+	ProcessedEmoji    string                  `toml:"processed_emoji"`
+	VoiceChannels     map[string]snowflake.ID `toml:"voice_channels"`
+	ExternalChannels  map[string]string       `toml:"external_channels"`
+	StageChannel      snowflake.ID            `toml:"stage_channel"`
+	SignupChannel     snowflake.ID            `toml:"signup_channel"`
+	DraftChannel      snowflake.ID            `toml:"draft_channel"`
+	MatchPollInterval int                     `toml:"match_poll_interval"`
+	MatchHours        map[string]int16        `toml:"match_hours"`
+	RollPolicy        string                  `toml:"roll_policy"`
+	Currency          string                  `toml:"currency"`
+}
+
+type RemindersConfig struct {
+	Channel         snowflake.ID `toml:"channel"`
+	GardenerOffsets []int        `toml:"gardener_offsets"`
+	ChannelOffsets  []int        `toml:"channel_offsets"`
+	Interval        int          `toml:"interval"`
+}
+
+type PredictionsConfig struct {
+	Channel            snowflake.ID      `toml:"channel"`
+	ReviewChannel      snowflake.ID      `toml:"review_channel"`
+	ResultPollInterval int               `toml:"result_poll_interval"`
+	OracleRoles        OracleRolesConfig `toml:"oracle_roles"`
+}
+
+type OracleRolesConfig struct {
+	Global snowflake.ID `toml:"global"`
+	Dota   snowflake.ID `toml:"dota"`
+	CS     snowflake.ID `toml:"cs"`
+	MLBB   snowflake.ID `toml:"mlbb"`
+	HoK    snowflake.ID `toml:"hok"`
+}
+
+type ModerationConfig struct {
+	ModLogChannel snowflake.ID `toml:"mod_log_channel"`
+}
+
+type HoneypotConfig struct {
+	Channels             []snowflake.ID `toml:"channels"`
+	ExemptRoles          []snowflake.ID `toml:"exempt_roles"`
+	DeleteMessageSeconds int            `toml:"delete_message_seconds"`
+	DryRun               bool           `toml:"dry_run"`
+}
+
+type SpamConfig struct {
+	Channels    int            `toml:"channels"`
+	Messages    int            `toml:"messages"`
+	Window      int            `toml:"window"`
+	Timeout     int            `toml:"timeout"`
+	ExemptRoles []snowflake.ID `toml:"exempt_roles"`
+}
+
+// PermissionsConfig maps command names to the roles allowed to run them.
+// Commands without an entry are open to everyone.
+type PermissionsConfig map[string][]snowflake.ID
+
+type RaidConfig struct {
+	Joins         int `toml:"joins"`
+	Window        int `toml:"window"`
+	MinAccountAge int `toml:"min_account_age"`
+	Timeout       int `toml:"timeout"`
 }
@@ -0,0 +1,167 @@
+package liquipedia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Dota2         = "dota2"
+	CounterStrike = "counterstrike"
+	MobileLegends = "mobilelegends"
+	HonorOfKings  = "honorofkings"
+)
+
+// Wikis are the wikis OG has teams on.
+var Wikis = []string{Dota2, CounterStrike, MobileLegends, HonorOfKings}
+
+// Games maps the game names used by commands and events to their wiki.
+var Games = map[string]string{
+	"Dota": Dota2,
+	"CS":   CounterStrike,
+	"MLBB": MobileLegends,
+	"HoK":  HonorOfKings,
+}
+
+type Config struct {
+	BaseURL         string            `toml:"base_url"`
+	APIKey          string            `toml:"api_key"`
+	Teams           map[string]string `toml:"teams"`
+	CacheTTL        int               `toml:"cache_ttl"`
+	RequestInterval int               `toml:"request_interval"`
+}
+
+// Client fetches OG matches from the Liquipedia match2 API, caching responses
+// and spacing requests to stay within the API terms of use.
+type Client struct {
+	cfg       Config
+	http      *http.Client
+	userAgent string
+
+	mu      sync.Mutex
+	cache   map[string]cacheEntry
+	nextReq time.Time
+}
+
+type cacheEntry struct {
+	matches []ResultElement
+	expires time.Time
+}
+
+func New(cfg Config, version string) *Client {
+	return &Client{
+		cfg:       cfg,
+		http:      &http.Client{Timeout: 10 * time.Second},
+		userAgent: "clockey/" + version,
+		cache:     make(map[string]cacheEntry),
+	}
+}
+
+// Team returns the name of OG's team on the wiki.
+func (c *Client) Team(wiki string) string {
+	if team, ok := c.cfg.Teams[wiki]; ok {
+		return team
+	}
+	return "OG"
+}
+
+// UpcomingMatches returns the unfinished matches of OG's team on the wiki,
+// soonest first.
+func (c *Client) UpcomingMatches(ctx context.Context, wiki string) ([]ResultElement, error) {
+	conditions := fmt.Sprintf("[[opponent::%s]] AND [[finished::0]]", c.Team(wiki))
+	return c.matches(ctx, wiki, conditions, "date ASC")
+}
+
+// FinishedMatches returns the matches of OG's team on the wiki that finished
+// after since, latest first.
+func (c *Client) FinishedMatches(ctx context.Context, wiki string, since time.Time) ([]ResultElement, error) {
+	conditions := fmt.Sprintf("[[opponent::%s]] AND [[finished::1]] AND [[date::>%s]]", c.Team(wiki), since.UTC().Format(time.DateTime))
+	return c.matches(ctx, wiki, conditions, "date DESC")
+}
+
+func (c *Client) matches(ctx context.Context, wiki string, conditions string, order string) ([]ResultElement, error) {
+	params := url.Values{}
+	params.Set("wiki", wiki)
+	params.Set("conditions", conditions)
+	params.Set("order", order)
+	params.Set("limit", "20")
+	key := params.Encode()
+
+	c.mu.Lock()
+	if entry, ok := c.cache[key]; ok && time.Now().Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.matches, nil
+	}
+	slot := c.reserve()
+	c.mu.Unlock()
+
+	if err := wait(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.cfg.BaseURL, "/")+"/match?"+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Apikey "+c.cfg.APIKey)
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("liquipedia returned %s for %s", resp.Status, wiki)
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode liquipedia response: %w", err)
+	}
+	if len(result.Error) > 0 {
+		return nil, fmt.Errorf("liquipedia error for %s: %s", wiki, strings.Join(result.Error, ", "))
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cacheEntry{
+		matches: result.Result,
+		expires: time.Now().Add(time.Duration(c.cfg.CacheTTL) * time.Second),
+	}
+	c.mu.Unlock()
+	return result.Result, nil
+}
+
+// reserve returns the time of the next free request slot and takes it. It
+// must be called with the mutex held.
+func (c *Client) reserve() time.Time {
+	slot := c.nextReq
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	c.nextReq = slot.Add(time.Duration(c.cfg.RequestInterval) * time.Second)
+	return slot
+}
+
+// wait blocks until the reserved slot or until ctx is done.
+func wait(ctx context.Context, slot time.Time) error {
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
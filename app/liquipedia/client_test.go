@@ -0,0 +1,254 @@
+package liquipedia_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"clockey/app/liquipedia"
+	"clockey/test"
+)
+
+func newClient(baseURL string) *liquipedia.Client {
+	return liquipedia.New(liquipedia.Config{
+		BaseURL:  baseURL,
+		APIKey:   "key",
+		CacheTTL: 60,
+	}, "test")
+}
+
+func TestUpcomingMatches(t *testing.T) {
+	server := test.NewLiquipediaServer()
+	defer server.Close()
+	client := newClient(server.URL)
+
+	matches, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	match := matches[0]
+	if want := time.Date(2026, 10, 21, 14, 0, 0, 0, time.UTC); !match.Date.Equal(want) {
+		t.Errorf("date = %s, want %s", match.Date, want)
+	}
+	if url := match.Stream.URL(); url != "https://www.twitch.tv/fissure_dota_en" {
+		t.Errorf("stream = %q", url)
+	}
+	us, them, ok := match.Sides(client.Team(liquipedia.Dota2))
+	if !ok || us.Name != "OG" || them.Name != "Team Liquid" {
+		t.Errorf("sides = %q, %q, %t", us.Name, them.Name, ok)
+	}
+
+	// OG is the second opponent on this one
+	matches, err = client.UpcomingMatches(context.Background(), liquipedia.CounterStrike)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	if us, them, ok := matches[0].Sides("OG"); !ok || us.Name != "OG" || them.Name != "SINNERS Esports" {
+		t.Errorf("sides = %q, %q, %t", us.Name, them.Name, ok)
+	}
+
+	matches, err = client.UpcomingMatches(context.Background(), liquipedia.HonorOfKings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("got %d matches for a wiki without fixtures, want 0", len(matches))
+	}
+}
+
+func TestFinishedMatches(t *testing.T) {
+	server := test.NewLiquipediaServer()
+	defer server.Close()
+	client := newClient(server.URL)
+
+	matches, err := client.FinishedMatches(context.Background(), liquipedia.Dota2, time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	match := matches[0]
+	if match.Finished != 1 || match.Winner != "1" || match.Bestof != 3 {
+		t.Errorf("finished = %d, winner = %q, bestof = %d", match.Finished, match.Winner, match.Bestof)
+	}
+	if match.Stream == nil || len(match.Stream) != 0 || match.Stream.URL() != "" {
+		t.Errorf("stream = %v, want empty", match.Stream)
+	}
+}
+
+func TestMatchesCached(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"result":[{"match2id":"a","date":"2026-10-21 14:00:00","stream":[]}]}`))
+	}))
+	defer server.Close()
+	client := newClient(server.URL)
+
+	for range 3 {
+		matches, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 || matches[0].Match2ID != "a" {
+			t.Fatalf("matches = %+v", matches)
+		}
+	}
+	if _, err := client.UpcomingMatches(context.Background(), liquipedia.CounterStrike); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestMatchesErrors(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		"non-200": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+		"result error": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"result":[],"error":["invalid conditions"]}`))
+		},
+		"invalid json": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html>`))
+		},
+		"invalid date": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"result":[{"date":"tomorrow"}]}`))
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := newClient(server.URL)
+
+			if _, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestMatchesErrorNotCached(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":[]}`))
+	}))
+	defer server.Close()
+	client := newClient(server.URL)
+
+	if _, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2); err == nil {
+		t.Fatal("expected an error")
+	}
+	fail.Store(false)
+	if _, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlowRequestDoesNotBlockCache(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("conditions"), "[[finished::1]]") {
+			<-release
+		}
+		_, _ = w.Write([]byte(`{"result":[]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client := newClient(server.URL)
+
+	if _, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2); err != nil {
+		t.Fatal(err)
+	}
+
+	slowCtx, cancelSlow := context.WithCancel(context.Background())
+	defer cancelSlow()
+	go func() {
+		_, _ = client.FinishedMatches(slowCtx, liquipedia.Dota2, time.Now())
+	}()
+	// Give the slow request time to start
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.UpcomingMatches(context.Background(), liquipedia.Dota2)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cached matches blocked by a slow request")
+	}
+}
+
+func TestDateUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{json: `"2026-10-21 14:00:00"`, want: time.Date(2026, 10, 21, 14, 0, 0, 0, time.UTC)},
+		{json: `""`, want: time.Time{}},
+		{json: `"21-10-2026"`, wantErr: true},
+		{json: `1761055200`, wantErr: true},
+	}
+	for _, tc := range tests {
+		var date liquipedia.Date
+		err := json.Unmarshal([]byte(tc.json), &date)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, want error %t", tc.json, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && !date.Equal(tc.want) {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tc.json, date.Time, tc.want)
+		}
+	}
+}
+
+func TestStreamUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		url     string
+		wantErr bool
+	}{
+		{json: `[]`, url: ""},
+		{json: `{}`, url: ""},
+		{json: `{"twitch":"ogdota"}`, url: "https://www.twitch.tv/ogdota"},
+		{json: `{"youtube":"@og","twitch2":"og2"}`, url: "https://www.youtube.com/@og"},
+		{json: `{"facebook":"og"}`, url: ""},
+		{json: `["twitch"]`, wantErr: true},
+	}
+	for _, tc := range tests {
+		var stream liquipedia.Stream
+		err := json.Unmarshal([]byte(tc.json), &stream)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, want error %t", tc.json, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && stream.URL() != tc.url {
+			t.Errorf("Unmarshal(%s).URL() = %q, want %q", tc.json, stream.URL(), tc.url)
+		}
+	}
+}
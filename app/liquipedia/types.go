@@ -0,0 +1,142 @@
+package liquipedia
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Result struct {
+	Result []ResultElement `json:"result"`
+	Error  []string        `json:"error"`
+}
+
+type ResultElement struct {
+	Date            Date             `json:"date"`
+	Dateexact       int64            `json:"dateexact"`
+	Match2ID        string           `json:"match2id"`
+	Pagename        string           `json:"pagename"`
+	Namespace       int64            `json:"namespace"`
+	Match2Opponents []Match2Opponent `json:"match2opponents"`
+	Wiki            string           `json:"wiki"`
+	Finished        int64            `json:"finished"`
+	Bestof          int64            `json:"bestof"`
+	Winner          string           `json:"winner"`
+	Tournament      string           `json:"tournament"`
+	Stream          Stream           `json:"stream"`
+}
+
+type Match2Opponent struct {
+	ID            int64          `json:"id"`
+	Type          string         `json:"type"`
+	Name          string         `json:"name"`
+	Template      string         `json:"template"`
+	Icon          string         `json:"icon"`
+	Score         int64          `json:"score"`
+	Status        string         `json:"status"`
+	Placement     int64          `json:"placement"`
+	Match2Players []Match2Player `json:"match2players"`
+	Extradata     []interface{}  `json:"extradata"`
+	Teamtemplate  Teamtemplate   `json:"teamtemplate"`
+}
+
+type Match2Player struct {
+	ID          int64         `json:"id"`
+	Opid        int64         `json:"opid"`
+	Name        string        `json:"name"`
+	Displayname string        `json:"displayname"`
+	Flag        Flag          `json:"flag"`
+	Extradata   []interface{} `json:"extradata"`
+}
+
+type Teamtemplate struct {
+	Template           string `json:"template"`
+	Page               string `json:"page"`
+	Name               string `json:"name"`
+	Shortname          string `json:"shortname"`
+	Bracketname        string `json:"bracketname"`
+	Image              string `json:"image"`
+	Imagedark          string `json:"imagedark"`
+	Legacyimage        string `json:"legacyimage"`
+	Legacyimagedark    string `json:"legacyimagedark"`
+	Imageurl           string `json:"imageurl"`
+	Imagedarkurl       string `json:"imagedarkurl"`
+	Legacyimageurl     string `json:"legacyimageurl"`
+	Legacyimagedarkurl string `json:"legacyimagedarkurl"`
+}
+
+type Flag string
+
+const (
+	Philippines Flag = "Philippines"
+	Russia      Flag = "Russia"
+	Ukraine     Flag = "Ukraine"
+)
+
+// Date is a match time as returned by the API, "2006-01-02 15:04:05" in UTC.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	t, err := time.ParseInLocation(time.DateTime, value, time.UTC)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+// Stream maps a platform to a channel. The API sends an empty array instead
+// of an empty object when a match has no streams.
+type Stream map[string]string
+
+func (s *Stream) UnmarshalJSON(data []byte) error {
+	if string(data) == "[]" {
+		*s = Stream{}
+		return nil
+	}
+	var streams map[string]string
+	if err := json.Unmarshal(data, &streams); err != nil {
+		return err
+	}
+	*s = streams
+	return nil
+}
+
+// URL links to the first stream on a platform we know the address of, or
+// returns an empty string.
+func (s Stream) URL() string {
+	platforms := []struct{ key, base string }{
+		{"twitch", "https://www.twitch.tv/"},
+		{"youtube", "https://www.youtube.com/"},
+		{"kick", "https://kick.com/"},
+		{"twitch2", "https://www.twitch.tv/"},
+	}
+	for _, platform := range platforms {
+		if channel := s[platform.key]; channel != "" {
+			return platform.base + channel
+		}
+	}
+	return ""
+}
+
+// Sides splits the opponents into the tracked team and the team it plays.
+// ok is false when the team isn't one of the opponents.
+func (r ResultElement) Sides(team string) (us Match2Opponent, them Match2Opponent, ok bool) {
+	if len(r.Match2Opponents) != 2 {
+		return us, them, false
+	}
+	us, them = r.Match2Opponents[0], r.Match2Opponents[1]
+	if them.Name == team {
+		us, them = them, us
+	}
+	return us, them, us.Name == team
+}
@@ -0,0 +1,3 @@
+DROP TABLE public.scoreboards;
+
+DROP TYPE public.scoreboard_game;
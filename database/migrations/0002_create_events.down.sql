@@ -0,0 +1,3 @@
+DROP TABLE public.events;
+
+DROP TYPE public.event_type;
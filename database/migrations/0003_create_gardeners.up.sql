@@ -0,0 +1,17 @@
+CREATE TABLE public.gardeners (
+    id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    CONSTRAINT gardeners_pkey PRIMARY KEY (id)
+) TABLESPACE pg_default;
+
+-- The roster that used to be hard-coded in the bot
+INSERT INTO public.gardeners (id, name)
+VALUES
+    (293360731867316225, 'N1k'),
+    (204923365205475329, 'Kit'),
+    (492549065041510403, 'Pupi'),
+    (754724309276164159, 'WW'),
+    (172360818715918337, 'Bonteng'),
+    (332438787588227072, 'Sam')
+ON CONFLICT (id) DO NOTHING;
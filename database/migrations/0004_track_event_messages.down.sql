@@ -0,0 +1,10 @@
+-- Signup posts that were never rolled have no gardener to keep
+DELETE FROM public.events
+WHERE gardener IS NULL;
+
+ALTER TABLE public.events
+    DROP CONSTRAINT events_message_id_key,
+    DROP COLUMN scheduled_event_id,
+    DROP COLUMN channel_id,
+    DROP COLUMN message_id,
+    ALTER COLUMN gardener SET NOT NULL;
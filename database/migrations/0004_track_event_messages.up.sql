@@ -0,0 +1,6 @@
+ALTER TABLE public.events
+    ALTER COLUMN gardener DROP NOT NULL,
+    ADD COLUMN message_id BIGINT,
+    ADD COLUMN channel_id BIGINT,
+    ADD COLUMN scheduled_event_id BIGINT,
+    ADD CONSTRAINT events_message_id_key UNIQUE (message_id);
@@ -0,0 +1,19 @@
+-- Archived seasons cannot be represented without the season column
+DELETE FROM public.scoreboards
+WHERE
+    season <> (
+        SELECT
+            id
+        FROM
+            public.seasons
+        WHERE
+            ended_at IS NULL
+    );
+
+ALTER TABLE public.scoreboards
+    DROP CONSTRAINT scoreboards_season_fkey,
+    DROP CONSTRAINT scoreboards_pkey,
+    DROP COLUMN season,
+    ADD CONSTRAINT scoreboards_pkey PRIMARY KEY (member, game);
+
+DROP TABLE public.seasons;
@@ -0,0 +1,35 @@
+CREATE TABLE public.seasons (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    name TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ended_at TIMESTAMPTZ,
+    CONSTRAINT seasons_pkey PRIMARY KEY (id)
+) TABLESPACE pg_default;
+
+-- Only one season can be open at a time
+CREATE UNIQUE INDEX seasons_open_key ON public.seasons ((ended_at IS NULL))
+WHERE
+    ended_at IS NULL;
+
+INSERT INTO
+    public.seasons (name)
+VALUES
+    (to_char(now(), 'FMMonth YYYY'));
+
+ALTER TABLE public.scoreboards
+    ADD COLUMN season BIGINT;
+
+UPDATE public.scoreboards
+SET
+    season = (
+        SELECT
+            id
+        FROM
+            public.seasons
+    );
+
+ALTER TABLE public.scoreboards
+    ALTER COLUMN season SET NOT NULL,
+    DROP CONSTRAINT scoreboards_pkey,
+    ADD CONSTRAINT scoreboards_pkey PRIMARY KEY (season, member, game),
+    ADD CONSTRAINT scoreboards_season_fkey FOREIGN KEY (season) REFERENCES public.seasons (id);
@@ -0,0 +1,3 @@
+DROP TABLE public.predictions;
+
+DROP TABLE public.prediction_matches;
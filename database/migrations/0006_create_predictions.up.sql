@@ -0,0 +1,19 @@
+CREATE TABLE public.prediction_matches (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    name TEXT NOT NULL,
+    game public.scoreboard_game NOT NULL,
+    series_length SMALLINT NOT NULL,
+    deadline BIGINT NOT NULL,
+    result TEXT,
+    message_id BIGINT,
+    channel_id BIGINT,
+    CONSTRAINT prediction_matches_pkey PRIMARY KEY (id)
+) TABLESPACE pg_default;
+
+CREATE TABLE public.predictions (
+    match BIGINT NOT NULL,
+    member BIGINT NOT NULL,
+    scoreline TEXT NOT NULL,
+    CONSTRAINT predictions_pkey PRIMARY KEY (match, member),
+    CONSTRAINT predictions_match_fkey FOREIGN KEY (match) REFERENCES public.prediction_matches (id) ON DELETE CASCADE
+) TABLESPACE pg_default;
@@ -0,0 +1,2 @@
+ALTER TABLE public.prediction_matches
+    DROP COLUMN opponent;
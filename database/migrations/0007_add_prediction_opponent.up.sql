@@ -0,0 +1,2 @@
+ALTER TABLE public.prediction_matches
+    ADD COLUMN opponent TEXT;
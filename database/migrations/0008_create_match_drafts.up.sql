@@ -0,0 +1,15 @@
+CREATE TABLE public.match_drafts (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    wiki TEXT NOT NULL,
+    match_id TEXT NOT NULL,
+    type public.event_type NOT NULL,
+    name TEXT NOT NULL,
+    time BIGINT NOT NULL,
+    hours SMALLINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    message_id BIGINT,
+    channel_id BIGINT,
+    CONSTRAINT match_drafts_pkey PRIMARY KEY (id),
+    CONSTRAINT match_drafts_match_key UNIQUE (wiki, match_id),
+    CONSTRAINT match_drafts_status_check CHECK (status IN ('pending', 'approved', 'rejected'))
+) TABLESPACE pg_default;
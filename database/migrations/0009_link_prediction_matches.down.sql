@@ -0,0 +1,4 @@
+ALTER TABLE public.prediction_matches
+    DROP COLUMN proposed_result,
+    DROP COLUMN liquipedia_match,
+    DROP COLUMN wiki;
@@ -0,0 +1,4 @@
+ALTER TABLE public.prediction_matches
+    ADD COLUMN wiki TEXT,
+    ADD COLUMN liquipedia_match TEXT,
+    ADD COLUMN proposed_result TEXT;
@@ -0,0 +1,10 @@
+CREATE TABLE public.event_reminders (
+    event BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    offset_seconds INTEGER NOT NULL,
+    event_time BIGINT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT event_reminders_pkey PRIMARY KEY (event, kind, offset_seconds, event_time),
+    CONSTRAINT event_reminders_event_fkey FOREIGN KEY (event) REFERENCES public.events (id) ON DELETE CASCADE,
+    CONSTRAINT event_reminders_kind_check CHECK (kind IN ('gardener', 'channel'))
+) TABLESPACE pg_default;
@@ -0,0 +1,16 @@
+-- Events worked by several gardeners keep only one of them
+ALTER TABLE public.events
+    ADD COLUMN gardener BIGINT;
+
+UPDATE public.events
+SET
+    gardener = (
+        SELECT
+            MIN(event_gardeners.gardener)
+        FROM
+            public.event_gardeners
+        WHERE
+            event_gardeners.event = events.id
+    );
+
+DROP TABLE public.event_gardeners;
@@ -0,0 +1,21 @@
+CREATE TABLE public.event_gardeners (
+    event BIGINT NOT NULL,
+    gardener BIGINT NOT NULL,
+    CONSTRAINT event_gardeners_pkey PRIMARY KEY (event, gardener),
+    CONSTRAINT event_gardeners_event_fkey FOREIGN KEY (event) REFERENCES public.events (id) ON DELETE CASCADE
+) TABLESPACE pg_default;
+
+CREATE INDEX event_gardeners_gardener_idx ON public.event_gardeners (gardener);
+
+INSERT INTO
+    public.event_gardeners (event, gardener)
+SELECT
+    id,
+    gardener
+FROM
+    public.events
+WHERE
+    gardener IS NOT NULL;
+
+ALTER TABLE public.events
+    DROP COLUMN gardener;
@@ -0,0 +1 @@
+DROP TABLE public.hourly_rates;
@@ -0,0 +1,11 @@
+-- A rate applies to a gardener, a game, both or everyone when neither is set,
+-- from effective_from until a later rate of the same scope takes over
+CREATE TABLE public.hourly_rates (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    gardener BIGINT,
+    type public.event_type,
+    rate_cents INTEGER NOT NULL,
+    effective_from BIGINT NOT NULL,
+    CONSTRAINT hourly_rates_pkey PRIMARY KEY (id),
+    CONSTRAINT hourly_rates_rate_cents_check CHECK (rate_cents >= 0)
+) TABLESPACE pg_default;
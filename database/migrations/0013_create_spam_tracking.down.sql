@@ -0,0 +1,2 @@
+DROP TABLE public.spam_messages;
+DROP TABLE public.spam_incidents;
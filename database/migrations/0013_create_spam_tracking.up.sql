@@ -0,0 +1,26 @@
+CREATE TABLE public.spam_incidents (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    guild_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at BIGINT NOT NULL,
+    CONSTRAINT spam_incidents_pkey PRIMARY KEY (id),
+    CONSTRAINT spam_incidents_status_check CHECK (status IN ('pending', 'banned', 'restored'))
+) TABLESPACE pg_default;
+
+-- Recent messages are only kept for the detection window, unless they are
+-- part of an incident and may need to be restored
+CREATE TABLE public.spam_messages (
+    message_id BIGINT NOT NULL,
+    channel_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    content_hash TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    incident BIGINT,
+    CONSTRAINT spam_messages_pkey PRIMARY KEY (message_id),
+    CONSTRAINT spam_messages_incident_fkey FOREIGN KEY (incident) REFERENCES public.spam_incidents (id) ON DELETE CASCADE
+) TABLESPACE pg_default;
+
+CREATE INDEX spam_messages_author_idx ON public.spam_messages (author_id, content_hash, created_at);
+CREATE INDEX spam_messages_incident_idx ON public.spam_messages (incident);
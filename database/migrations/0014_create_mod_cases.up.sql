@@ -0,0 +1,15 @@
+-- Automated actions have no moderator
+CREATE TABLE public.mod_cases (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    guild_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    moderator_id BIGINT,
+    reason TEXT NOT NULL,
+    evidence TEXT,
+    created_at BIGINT NOT NULL,
+    CONSTRAINT mod_cases_pkey PRIMARY KEY (id),
+    CONSTRAINT mod_cases_action_check CHECK (action IN ('ban', 'timeout', 'warn', 'unban'))
+) TABLESPACE pg_default;
+
+CREATE INDEX mod_cases_user_idx ON public.mod_cases (user_id, created_at);
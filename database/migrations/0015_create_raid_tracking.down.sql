@@ -0,0 +1,2 @@
+DROP TABLE public.lockdowns;
+DROP TABLE public.member_joins;
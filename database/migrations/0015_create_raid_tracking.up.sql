@@ -0,0 +1,23 @@
+-- Joins are only kept for the detection window
+CREATE TABLE public.member_joins (
+    guild_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    joined_at BIGINT NOT NULL,
+    CONSTRAINT member_joins_pkey PRIMARY KEY (guild_id, user_id)
+) TABLESPACE pg_default;
+
+CREATE INDEX member_joins_joined_at_idx ON public.member_joins (guild_id, joined_at);
+
+-- The verification level before the lockdown is restored when it ends
+CREATE TABLE public.lockdowns (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    guild_id BIGINT NOT NULL,
+    verification_level INT NOT NULL,
+    started_at BIGINT NOT NULL,
+    ended_at BIGINT,
+    ended_by BIGINT,
+    CONSTRAINT lockdowns_pkey PRIMARY KEY (id)
+) TABLESPACE pg_default;
+
+-- A guild has at most one active lockdown
+CREATE UNIQUE INDEX lockdowns_active_idx ON public.lockdowns (guild_id) WHERE ended_at IS NULL;
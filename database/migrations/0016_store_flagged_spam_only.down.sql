@@ -0,0 +1,8 @@
+ALTER TABLE public.spam_messages
+ADD COLUMN content_hash TEXT NOT NULL DEFAULT '',
+ALTER COLUMN incident DROP NOT NULL;
+
+ALTER TABLE public.spam_messages
+ALTER COLUMN content_hash DROP DEFAULT;
+
+CREATE INDEX spam_messages_author_idx ON public.spam_messages (author_id, content_hash, created_at);
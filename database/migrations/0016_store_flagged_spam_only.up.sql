@@ -0,0 +1,11 @@
+-- Recent messages are tracked in memory, only the copies of an incident are
+-- kept so they can be restored
+DELETE FROM public.spam_messages
+WHERE
+    incident IS NULL;
+
+DROP INDEX public.spam_messages_author_idx;
+
+ALTER TABLE public.spam_messages
+DROP COLUMN content_hash,
+ALTER COLUMN incident SET NOT NULL;
@@ -0,0 +1,9 @@
+UPDATE public.mod_cases
+SET
+    action = 'unban'
+WHERE
+    action = 'untimeout';
+
+ALTER TABLE public.mod_cases
+    DROP CONSTRAINT mod_cases_action_check,
+    ADD CONSTRAINT mod_cases_action_check CHECK (action IN ('ban', 'timeout', 'warn', 'unban'));
@@ -0,0 +1,11 @@
+ALTER TABLE public.mod_cases
+    DROP CONSTRAINT mod_cases_action_check,
+    ADD CONSTRAINT mod_cases_action_check CHECK (action IN ('ban', 'timeout', 'untimeout', 'warn', 'unban'));
+
+-- Reverted spam flags lifted a timeout but were recorded as unbans
+UPDATE public.mod_cases
+SET
+    action = 'untimeout'
+WHERE
+    action = 'unban'
+    AND reason = 'Spam flag reverted';
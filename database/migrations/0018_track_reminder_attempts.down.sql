@@ -0,0 +1,19 @@
+DELETE FROM public.event_reminders
+WHERE
+    sent_at IS NULL;
+
+DELETE FROM public.event_reminders duplicate USING public.event_reminders kept
+WHERE
+    duplicate.event = kept.event
+    AND duplicate.kind = kept.kind
+    AND duplicate.offset_seconds = kept.offset_seconds
+    AND duplicate.event_time = kept.event_time
+    AND duplicate.recipient > kept.recipient;
+
+ALTER TABLE public.event_reminders
+    DROP CONSTRAINT event_reminders_pkey,
+    ADD CONSTRAINT event_reminders_pkey PRIMARY KEY (event, kind, offset_seconds, event_time),
+    DROP COLUMN recipient,
+    DROP COLUMN attempts,
+    ALTER COLUMN sent_at SET DEFAULT now(),
+    ALTER COLUMN sent_at SET NOT NULL;
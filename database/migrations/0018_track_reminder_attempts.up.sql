@@ -0,0 +1,34 @@
+-- Gardener reminders are tracked per gardener, channel reminders use 0
+ALTER TABLE public.event_reminders
+    ADD COLUMN recipient BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN attempts SMALLINT NOT NULL DEFAULT 1,
+    ALTER COLUMN sent_at DROP NOT NULL,
+    ALTER COLUMN sent_at DROP DEFAULT,
+    DROP CONSTRAINT event_reminders_pkey,
+    ADD CONSTRAINT event_reminders_pkey PRIMARY KEY (event, kind, recipient, offset_seconds, event_time);
+
+-- Reminders already sent went to every gardener of the event
+INSERT INTO
+    public.event_reminders (event, kind, recipient, offset_seconds, event_time, attempts, sent_at)
+SELECT
+    event_reminders.event,
+    event_reminders.kind,
+    event_gardeners.gardener,
+    event_reminders.offset_seconds,
+    event_reminders.event_time,
+    event_reminders.attempts,
+    event_reminders.sent_at
+FROM
+    public.event_reminders
+    JOIN public.event_gardeners ON event_gardeners.event = event_reminders.event
+WHERE
+    event_reminders.kind = 'gardener';
+
+DELETE FROM public.event_reminders
+WHERE
+    kind = 'gardener'
+    AND recipient = 0;
+
+ALTER TABLE public.event_reminders
+    ALTER COLUMN recipient DROP DEFAULT,
+    ALTER COLUMN attempts DROP DEFAULT;
@@ -0,0 +1,263 @@
+package migrations
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockID serialises migration runs between instances starting at the same time.
+const lockID = 7436261
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// baselines are the migrations of the schema that predates migrations, with
+// the table each of them creates.
+var baselines = map[int64]string{
+	1: "public.scoreboards",
+	2: "public.events",
+}
+
+// Load reads the embedded migrations, named NNNN_name.up.sql and
+// NNNN_name.down.sql, ordered by version.
+func Load() ([]Migration, error) {
+	return load(files)
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		base, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		versionString, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseInt(versionString, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing its up or down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the ones that were applied.
+func Up(ctx context.Context, conn *pgxpool.Pool) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		ran, err := run(ctx, conn, migrations, func(tx pgx.Tx, versions map[int64]time.Time) (bool, error) {
+			if _, exists := versions[migration.Version]; exists {
+				return false, nil
+			}
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return false, err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			return true, err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+// Down reverts the most recently applied migration. It returns false when
+// there is nothing left to revert.
+func Down(ctx context.Context, conn *pgxpool.Pool) (Migration, bool, error) {
+	migrations, err := Load()
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	var reverted Migration
+	ran, err := run(ctx, conn, migrations, func(tx pgx.Tx, versions map[int64]time.Time) (bool, error) {
+		for _, migration := range slices.Backward(migrations) {
+			if _, exists := versions[migration.Version]; !exists {
+				continue
+			}
+			reverted = migration
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return false, err
+			}
+			_, err := tx.Exec(ctx, "DELETE FROM public.schema_migrations WHERE version = $1", migration.Version)
+			return true, err
+		}
+		return false, nil
+	})
+	if err != nil {
+		return reverted, false, fmt.Errorf("failed to revert migration %d_%s: %w", reverted.Version, reverted.Name, err)
+	}
+	return reverted, ran, nil
+}
+
+// List reports every known migration and when it was applied, if at all.
+func List(ctx context.Context, conn *pgxpool.Pool) ([]Status, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	_, err = run(ctx, conn, migrations, func(tx pgx.Tx, versions map[int64]time.Time) (bool, error) {
+		for _, migration := range migrations {
+			status := Status{Migration: migration}
+			if appliedAt, exists := versions[migration.Version]; exists {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return false, nil
+	})
+	return statuses, err
+}
+
+// run opens a transaction holding the migration lock, makes sure the
+// schema_migrations table exists and hands the applied versions to fn.
+func run(ctx context.Context, conn *pgxpool.Pool, migrations []Migration, fn func(tx pgx.Tx, versions map[int64]time.Time) (bool, error)) (bool, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockID); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS public.schema_migrations (
+    version BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT schema_migrations_pkey PRIMARY KEY (version)
+)`); err != nil {
+		return false, err
+	}
+
+	rows, err := tx.Query(ctx, "SELECT version, applied_at FROM public.schema_migrations")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return false, err
+		}
+		versions[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	if err := baseline(ctx, tx, migrations, versions); err != nil {
+		return false, err
+	}
+
+	ran, err := fn(tx, versions)
+	if err != nil {
+		return false, err
+	}
+	return ran, tx.Commit(ctx)
+}
+
+type execQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// baseline records the baseline migrations as applied when their table
+// already exists, so databases created before migrations don't rerun them.
+func baseline(ctx context.Context, tx execQuerier, migrations []Migration, versions map[int64]time.Time) error {
+	for _, migration := range migrations {
+		table, ok := baselines[migration.Version]
+		if !ok {
+			continue
+		}
+		if _, applied := versions[migration.Version]; applied {
+			continue
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if _, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+			return err
+		}
+		versions[migration.Version] = time.Now()
+	}
+	return nil
+}
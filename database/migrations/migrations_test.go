@@ -0,0 +1,154 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s at position %d, want version %d", migration.Version, migration.Name, i, i+1)
+		}
+	}
+}
+
+func TestLoadOrdersAndPairs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_later.up.sql":   {Data: []byte("up 10")},
+		"0010_later.down.sql": {Data: []byte("down 10")},
+		"0002_first.up.sql":   {Data: []byte("up 2")},
+		"0002_first.down.sql": {Data: []byte("down 2")},
+	}
+	migrations, err := load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(migrations))
+	}
+	want := []Migration{
+		{Version: 2, Name: "first", Up: "up 2", Down: "down 2"},
+		{Version: 10, Name: "later", Up: "up 10", Down: "down 10"},
+	}
+	for i := range want {
+		if migrations[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, migrations[i], want[i])
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_a.up.sql": {Data: []byte("up")},
+		},
+		"missing up": {
+			"0001_a.down.sql": {Data: []byte("down")},
+		},
+		"conflicting names": {
+			"0001_a.up.sql":   {Data: []byte("up")},
+			"0001_b.down.sql": {Data: []byte("down")},
+		},
+		"invalid direction": {
+			"0001_a.sideways.sql": {Data: []byte("up")},
+		},
+		"invalid version": {
+			"one_a.up.sql":   {Data: []byte("up")},
+			"one_a.down.sql": {Data: []byte("down")},
+		},
+		"missing name": {
+			"0001.up.sql": {Data: []byte("up")},
+		},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := load(fsys); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+type fakeTx struct {
+	tables   map[string]bool
+	inserted []int64
+}
+
+func (tx *fakeTx) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	tx.inserted = append(tx.inserted, args[0].(int64))
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *fakeTx) QueryRow(_ context.Context, _ string, args ...any) pgx.Row {
+	return fakeRow(tx.tables[args[0].(string)])
+}
+
+type fakeRow bool
+
+func (r fakeRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+func TestBaseline(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create_scoreboards"},
+		{Version: 2, Name: "create_events"},
+		{Version: 3, Name: "create_gardeners"},
+	}
+	tests := map[string]struct {
+		tables  map[string]bool
+		applied []int64
+		want    []int64
+	}{
+		"fresh database": {
+			want: nil,
+		},
+		"existing schema": {
+			tables: map[string]bool{"public.scoreboards": true, "public.events": true},
+			want:   []int64{1, 2},
+		},
+		"only scoreboards": {
+			tables: map[string]bool{"public.scoreboards": true},
+			want:   []int64{1},
+		},
+		"already recorded": {
+			tables:  map[string]bool{"public.scoreboards": true, "public.events": true},
+			applied: []int64{1, 2},
+			want:    nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := &fakeTx{tables: tc.tables}
+			versions := make(map[int64]time.Time)
+			for _, version := range tc.applied {
+				versions[version] = time.Now()
+			}
+			if err := baseline(context.Background(), tx, migrations, versions); err != nil {
+				t.Fatal(err)
+			}
+			if len(tx.inserted) != len(tc.want) {
+				t.Fatalf("recorded %v, want %v", tx.inserted, tc.want)
+			}
+			for i, version := range tc.want {
+				if tx.inserted[i] != version {
+					t.Errorf("recorded %v, want %v", tx.inserted, tc.want)
+				}
+				if _, ok := versions[version]; !ok {
+					t.Errorf("version %d not marked as applied", version)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,26 @@
--- name: CreateEvent :exec
-INSERT INTO public.events (name, time, type, gardener, hours) VALUES ($1, $2, $3, $4, $5);
+-- name: CreateEvent :one
+INSERT INTO public.events (name, time, type, hours, message_id, channel_id, scheduled_event_id) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
 
--- name: DeleteEvent :exec
+-- name: GetEventByMessage :one
+SELECT
+    *
+FROM
+    public.events
+WHERE message_id = $1;
+
+-- name: DeleteEvent :execrows
 DELETE FROM public.events
-WHERE name = $1 AND time = $2 AND type = $3 AND hours = $4;
+WHERE id = $1;
 
 -- name: GetEventsForGardener :many
 SELECT
-    *
+    events.*
 FROM
     public.events
+    JOIN public.event_gardeners ON event_gardeners.event = events.id
 WHERE time BETWEEN @start_time AND @end_time
-AND gardener = $1;
+AND event_gardeners.gardener = $1;
 
 -- name: GetEventsForGame :many
 SELECT
@@ -19,4 +28,54 @@ SELECT
 FROM
     public.events
 WHERE time BETWEEN @start_time AND @end_time
-AND type = $1;
+AND type = $1
+AND EXISTS (SELECT 1 FROM public.event_gardeners WHERE event_gardeners.event = events.id);
+
+-- name: UpdateEvent :exec
+UPDATE public.events
+SET name = $2, time = $3, hours = $4
+WHERE id = $1;
+
+-- name: ListUpcomingEvents :many
+SELECT
+    *
+FROM
+    public.events
+WHERE time >= $1
+ORDER BY time;
+
+-- name: GetLastEventTimeForGardener :one
+SELECT
+    COALESCE(MAX(events.time), 0)::BIGINT AS last_time
+FROM
+    public.events
+    JOIN public.event_gardeners ON event_gardeners.event = events.id
+WHERE event_gardeners.gardener = $1;
+
+-- name: LockEvent :one
+SELECT
+    id
+FROM
+    public.events
+WHERE
+    id = $1
+FOR UPDATE;
+
+-- name: TrackLegacyEvent :one
+UPDATE public.events
+SET message_id = $5, channel_id = $6
+WHERE id = (
+    SELECT
+        id
+    FROM
+        public.events
+    WHERE message_id IS NULL
+    AND name = $1
+    AND time = $2
+    AND type = $3
+    AND hours = $4
+    ORDER BY id
+    LIMIT 1
+)
+AND message_id IS NULL
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: AddEventGardener :exec
+INSERT INTO
+    public.event_gardeners (event, gardener)
+VALUES
+    ($1, $2) ON CONFLICT ON CONSTRAINT event_gardeners_pkey DO NOTHING;
+
+-- name: ListEventGardeners :many
+SELECT
+    gardener
+FROM
+    public.event_gardeners
+WHERE
+    event = $1
+ORDER BY
+    gardener;
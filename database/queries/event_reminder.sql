@@ -0,0 +1,22 @@
+-- name: ClaimEventReminder :execrows
+INSERT INTO
+    public.event_reminders (event, kind, recipient, offset_seconds, event_time, attempts)
+VALUES
+    ($1, $2, $3, $4, $5, 1) ON CONFLICT ON CONSTRAINT event_reminders_pkey DO
+UPDATE
+SET
+    attempts = event_reminders.attempts + 1
+WHERE
+    event_reminders.sent_at IS NULL
+    AND event_reminders.attempts < @max_attempts;
+
+-- name: MarkEventRemindersSent :exec
+UPDATE public.event_reminders
+SET
+    sent_at = now()
+WHERE
+    event = $1
+    AND kind = $2
+    AND recipient = $3
+    AND event_time = $4
+    AND sent_at IS NULL;
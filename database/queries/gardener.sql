@@ -0,0 +1,41 @@
+-- name: CreateGardener :exec
+INSERT INTO
+    public.gardeners (id, name)
+VALUES
+    ($1, $2) ON CONFLICT ON CONSTRAINT gardeners_pkey DO
+UPDATE
+SET
+    name = EXCLUDED.name,
+    active = TRUE;
+
+-- name: RemoveGardener :execrows
+UPDATE public.gardeners
+SET active = FALSE
+WHERE id = $1 AND active;
+
+-- name: RenameGardener :execrows
+UPDATE public.gardeners
+SET name = $2
+WHERE id = $1;
+
+-- name: GetGardener :one
+SELECT
+    *
+FROM
+    public.gardeners
+WHERE id = $1;
+
+-- name: ListGardeners :many
+SELECT
+    *
+FROM
+    public.gardeners
+WHERE active
+ORDER BY name;
+
+-- name: ListAllGardeners :many
+SELECT
+    *
+FROM
+    public.gardeners
+ORDER BY name;
@@ -0,0 +1,21 @@
+-- name: CreateHourlyRate :one
+INSERT INTO
+    public.hourly_rates (gardener, type, rate_cents, effective_from)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING
+    *;
+
+-- name: ListHourlyRates :many
+SELECT
+    *
+FROM
+    public.hourly_rates
+ORDER BY
+    effective_from,
+    id;
+
+-- name: DeleteHourlyRate :execrows
+DELETE FROM public.hourly_rates
+WHERE
+    id = $1;
@@ -0,0 +1,39 @@
+-- name: CreateMatchDraft :one
+INSERT INTO
+    public.match_drafts (wiki, match_id, type, name, time, hours)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (wiki, match_id) DO UPDATE
+SET
+    name = EXCLUDED.name,
+    time = EXCLUDED.time,
+    hours = EXCLUDED.hours
+WHERE
+    match_drafts.status = 'pending'
+    AND (match_drafts.name, match_drafts.time, match_drafts.hours) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.time, EXCLUDED.hours)
+RETURNING
+    *;
+
+-- name: SetMatchDraftMessage :exec
+UPDATE public.match_drafts
+SET
+    message_id = $2,
+    channel_id = $3
+WHERE
+    id = $1;
+
+-- name: GetMatchDraft :one
+SELECT
+    *
+FROM
+    public.match_drafts
+WHERE
+    id = $1;
+
+-- name: SetMatchDraftStatus :execrows
+UPDATE public.match_drafts
+SET
+    status = $2
+WHERE
+    id = $1
+    AND status = 'pending';
@@ -0,0 +1,26 @@
+-- name: CreateModCase :one
+INSERT INTO
+    public.mod_cases (guild_id, user_id, action, moderator_id, reason, evidence, created_at)
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7)
+RETURNING
+    *;
+
+-- name: GetModCase :one
+SELECT
+    *
+FROM
+    public.mod_cases
+WHERE
+    id = $1;
+
+-- name: ListModCasesForUser :many
+SELECT
+    *
+FROM
+    public.mod_cases
+WHERE
+    user_id = $1
+ORDER BY
+    created_at DESC,
+    id DESC;
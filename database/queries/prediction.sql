@@ -0,0 +1,92 @@
+-- name: CreatePredictionMatch :one
+INSERT INTO
+    public.prediction_matches (name, game, series_length, deadline, opponent, wiki, liquipedia_match)
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7)
+RETURNING
+    *;
+
+-- name: SetPredictionMatchMessage :exec
+UPDATE public.prediction_matches
+SET
+    message_id = $2,
+    channel_id = $3
+WHERE
+    id = $1;
+
+-- name: GetPredictionMatch :one
+SELECT
+    *
+FROM
+    public.prediction_matches
+WHERE
+    id = $1;
+
+-- name: ListOpenPredictionMatches :many
+SELECT
+    *
+FROM
+    public.prediction_matches
+WHERE
+    result IS NULL
+ORDER BY
+    deadline;
+
+-- name: ResolvePredictionMatch :execrows
+UPDATE public.prediction_matches
+SET
+    result = $2
+WHERE
+    id = $1
+    AND result IS NULL;
+
+-- name: SetPrediction :exec
+INSERT INTO
+    public.predictions (match, member, scoreline)
+VALUES
+    ($1, $2, $3) ON CONFLICT ON CONSTRAINT predictions_pkey DO
+UPDATE
+SET
+    scoreline = EXCLUDED.scoreline;
+
+-- name: ListCorrectPredictions :many
+SELECT
+    predictions.member
+FROM
+    public.predictions
+    JOIN public.prediction_matches ON prediction_matches.id = predictions.match
+WHERE
+    predictions.match = $1
+    AND predictions.scoreline = prediction_matches.result
+ORDER BY
+    predictions.member;
+
+-- name: ListUnreviewedPredictionMatches :many
+SELECT
+    *
+FROM
+    public.prediction_matches
+WHERE
+    result IS NULL
+    AND proposed_result IS NULL
+    AND liquipedia_match IS NOT NULL
+    AND deadline BETWEEN @since AND @until
+ORDER BY
+    deadline;
+
+-- name: ProposePredictionResult :execrows
+UPDATE public.prediction_matches
+SET
+    proposed_result = $2
+WHERE
+    id = $1
+    AND result IS NULL
+    AND proposed_result IS NULL;
+
+-- name: ClearProposedPredictionResult :exec
+UPDATE public.prediction_matches
+SET
+    proposed_result = NULL
+WHERE
+    id = $1
+    AND result IS NULL;
@@ -0,0 +1,60 @@
+-- name: TrackMemberJoin :exec
+INSERT INTO
+    public.member_joins (guild_id, user_id, joined_at)
+VALUES
+    ($1, $2, $3) ON CONFLICT ON CONSTRAINT member_joins_pkey DO
+UPDATE
+SET
+    joined_at = EXCLUDED.joined_at;
+
+-- name: PruneMemberJoins :exec
+DELETE FROM public.member_joins
+WHERE
+    joined_at < $1;
+
+-- name: ListRecentJoins :many
+SELECT
+    *
+FROM
+    public.member_joins
+WHERE
+    guild_id = $1
+    AND joined_at >= @since
+ORDER BY
+    joined_at;
+
+-- name: CreateLockdown :one
+INSERT INTO
+    public.lockdowns (guild_id, verification_level, started_at)
+VALUES
+    ($1, $2, $3) ON CONFLICT (guild_id)
+WHERE
+    ended_at IS NULL DO NOTHING
+RETURNING
+    *;
+
+-- name: GetLockdown :one
+SELECT
+    *
+FROM
+    public.lockdowns
+WHERE
+    id = $1;
+
+-- name: GetActiveLockdown :one
+SELECT
+    *
+FROM
+    public.lockdowns
+WHERE
+    guild_id = $1
+    AND ended_at IS NULL;
+
+-- name: EndLockdown :execrows
+UPDATE public.lockdowns
+SET
+    ended_at = $2,
+    ended_by = $3
+WHERE
+    id = $1
+    AND ended_at IS NULL;
@@ -1,8 +1,20 @@
 -- name: UpdateScoreboardForGame :exec
 INSERT INTO
-    public.scoreboards (member, score, game)
+    public.scoreboards (member, score, game, season)
 VALUES
-    ($1, 1, $2) ON CONFLICT ON CONSTRAINT scoreboards_pkey DO
+    (
+        $1,
+        1,
+        $2,
+        (
+            SELECT
+                id
+            FROM
+                public.seasons
+            WHERE
+                ended_at IS NULL
+        )
+    ) ON CONFLICT ON CONSTRAINT scoreboards_pkey DO
 UPDATE
 SET
     score = scoreboards.score + 1;
@@ -18,21 +30,31 @@ SELECT
 FROM
     public.scoreboards
 WHERE
-    game = $1;
+    game = $1
+    AND season = $2
+ORDER BY
+    position,
+    member;
 
 -- name: GetMemberScoreForGame :one
 SELECT
-    DENSE_RANK() OVER (
-        ORDER BY
-            score DESC
-    ) position,
-    member,
-    score
-FROM
-    public.scoreboards
+    *
+FROM (
+    SELECT
+        DENSE_RANK() OVER (
+            ORDER BY
+                score DESC
+        ) position,
+        member,
+        score
+    FROM
+        public.scoreboards
+    WHERE
+        game = $1
+        AND season = $3
+)
 WHERE
-    game = $1
-    AND member = $2;
+    member = $2;
 
 -- name: GetWinnerForGame :many
 SELECT
@@ -49,6 +71,7 @@ FROM (
         public.scoreboards
     WHERE
         game = $1
+        AND season = $2
 )
 WHERE
     position = 1;
@@ -63,22 +86,33 @@ SELECT
     sum(score) AS score
 FROM
     public.scoreboards
+WHERE
+    season = $1
 GROUP BY
+    member
+ORDER BY
+    position,
     member;
 
 -- name: GetMemberGlobalScore :one
 SELECT
-    DENSE_RANK() OVER (
-        ORDER BY
-            sum(score) DESC
-    ) AS position,
-    member,
-    sum(score) AS score
-FROM
-    public.scoreboards
-GROUP BY
-    member
-HAVING
+    *
+FROM (
+    SELECT
+        DENSE_RANK() OVER (
+            ORDER BY
+                sum(score) DESC
+        ) AS position,
+        member,
+        sum(score) AS score
+    FROM
+        public.scoreboards
+    WHERE
+        season = $2
+    GROUP BY
+        member
+)
+WHERE
     member = $1;
 
 -- name: GetGlobalWinner :many
@@ -93,6 +127,8 @@ WITH
             sum(score) AS score
         FROM
             public.scoreboards
+        WHERE
+            season = $1
         GROUP BY
             member
     )
@@ -102,6 +138,3 @@ FROM
     GlobalRankedLeaderboard
 WHERE
     position = 1;
-
--- name: ClearScoreboard :exec
-TRUNCATE TABLE public.scoreboards;
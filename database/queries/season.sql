@@ -0,0 +1,40 @@
+-- name: GetCurrentSeason :one
+SELECT
+    *
+FROM
+    public.seasons
+WHERE
+    ended_at IS NULL;
+
+-- name: GetSeason :one
+SELECT
+    *
+FROM
+    public.seasons
+WHERE
+    id = $1;
+
+-- name: ListSeasons :many
+SELECT
+    *
+FROM
+    public.seasons
+ORDER BY
+    id DESC;
+
+-- name: CloseCurrentSeason :one
+UPDATE public.seasons
+SET
+    ended_at = now()
+WHERE
+    ended_at IS NULL
+RETURNING
+    *;
+
+-- name: CreateSeason :one
+INSERT INTO
+    public.seasons (name)
+VALUES
+    ($1)
+RETURNING
+    *;
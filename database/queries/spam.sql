@@ -0,0 +1,39 @@
+-- name: CreateSpamIncident :one
+INSERT INTO
+    public.spam_incidents (guild_id, author_id, created_at)
+VALUES
+    ($1, $2, $3)
+RETURNING
+    *;
+
+-- name: CreateSpamMessage :exec
+INSERT INTO
+    public.spam_messages (message_id, channel_id, author_id, content, created_at, incident)
+VALUES
+    ($1, $2, $3, $4, $5, $6) ON CONFLICT ON CONSTRAINT spam_messages_pkey DO NOTHING;
+
+-- name: GetSpamIncident :one
+SELECT
+    *
+FROM
+    public.spam_incidents
+WHERE
+    id = $1;
+
+-- name: ListSpamIncidentMessages :many
+SELECT
+    *
+FROM
+    public.spam_messages
+WHERE
+    incident = $1
+ORDER BY
+    created_at;
+
+-- name: SetSpamIncidentStatus :execrows
+UPDATE public.spam_incidents
+SET
+    status = $2
+WHERE
+    id = $1
+    AND status = 'pending';
@@ -7,60 +7,94 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createEvent = `-- name: CreateEvent :exec
-INSERT INTO public.events (name, time, type, gardener, hours) VALUES ($1, $2, $3, $4, $5)
+const createEvent = `-- name: CreateEvent :one
+INSERT INTO public.events (name, time, type, hours, message_id, channel_id, scheduled_event_id) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, name, time, type, hours, message_id, channel_id, scheduled_event_id
 `
 
 type CreateEventParams struct {
-	Name     string
-	Time     int64
-	Type     EventType
-	Gardener int64
-	Hours    int16
+	Name             string
+	Time             int64
+	Type             EventType
+	Hours            int16
+	MessageID        pgtype.Int8
+	ChannelID        pgtype.Int8
+	ScheduledEventID pgtype.Int8
 }
 
-func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error {
-	_, err := q.db.Exec(ctx, createEvent,
+func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error) {
+	row := q.db.QueryRow(ctx, createEvent,
 		arg.Name,
 		arg.Time,
 		arg.Type,
-		arg.Gardener,
 		arg.Hours,
+		arg.MessageID,
+		arg.ChannelID,
+		arg.ScheduledEventID,
 	)
-	return err
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Time,
+		&i.Type,
+		&i.Hours,
+		&i.MessageID,
+		&i.ChannelID,
+		&i.ScheduledEventID,
+	)
+	return i, err
 }
 
-const deleteEvent = `-- name: DeleteEvent :exec
+const deleteEvent = `-- name: DeleteEvent :execrows
 DELETE FROM public.events
-WHERE name = $1 AND time = $2 AND type = $3 AND hours = $4
+WHERE id = $1
 `
 
-type DeleteEventParams struct {
-	Name  string
-	Time  int64
-	Type  EventType
-	Hours int16
+func (q *Queries) DeleteEvent(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteEvent, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-func (q *Queries) DeleteEvent(ctx context.Context, arg DeleteEventParams) error {
-	_, err := q.db.Exec(ctx, deleteEvent,
-		arg.Name,
-		arg.Time,
-		arg.Type,
-		arg.Hours,
+const getEventByMessage = `-- name: GetEventByMessage :one
+SELECT
+    id, name, time, type, hours, message_id, channel_id, scheduled_event_id
+FROM
+    public.events
+WHERE message_id = $1
+`
+
+func (q *Queries) GetEventByMessage(ctx context.Context, messageID pgtype.Int8) (Event, error) {
+	row := q.db.QueryRow(ctx, getEventByMessage, messageID)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Time,
+		&i.Type,
+		&i.Hours,
+		&i.MessageID,
+		&i.ChannelID,
+		&i.ScheduledEventID,
 	)
-	return err
+	return i, err
 }
 
 const getEventsForGame = `-- name: GetEventsForGame :many
 SELECT
-    id, name, time, type, gardener, hours
+    id, name, time, type, hours, message_id, channel_id, scheduled_event_id
 FROM
     public.events
 WHERE time BETWEEN $2 AND $3
 AND type = $1
+AND EXISTS (SELECT 1 FROM public.event_gardeners WHERE event_gardeners.event = events.id)
 `
 
 type GetEventsForGameParams struct {
@@ -83,8 +117,10 @@ func (q *Queries) GetEventsForGame(ctx context.Context, arg GetEventsForGamePara
 			&i.Name,
 			&i.Time,
 			&i.Type,
-			&i.Gardener,
 			&i.Hours,
+			&i.MessageID,
+			&i.ChannelID,
+			&i.ScheduledEventID,
 		); err != nil {
 			return nil, err
 		}
@@ -98,11 +134,12 @@ func (q *Queries) GetEventsForGame(ctx context.Context, arg GetEventsForGamePara
 
 const getEventsForGardener = `-- name: GetEventsForGardener :many
 SELECT
-    id, name, time, type, gardener, hours
+    events.id, events.name, events.time, events.type, events.hours, events.message_id, events.channel_id, events.scheduled_event_id
 FROM
     public.events
+    JOIN public.event_gardeners ON event_gardeners.event = events.id
 WHERE time BETWEEN $2 AND $3
-AND gardener = $1
+AND event_gardeners.gardener = $1
 `
 
 type GetEventsForGardenerParams struct {
@@ -125,8 +162,10 @@ func (q *Queries) GetEventsForGardener(ctx context.Context, arg GetEventsForGard
 			&i.Name,
 			&i.Time,
 			&i.Type,
-			&i.Gardener,
 			&i.Hours,
+			&i.MessageID,
+			&i.ChannelID,
+			&i.ScheduledEventID,
 		); err != nil {
 			return nil, err
 		}
@@ -137,3 +176,148 @@ func (q *Queries) GetEventsForGardener(ctx context.Context, arg GetEventsForGard
 	}
 	return items, nil
 }
+
+const getLastEventTimeForGardener = `-- name: GetLastEventTimeForGardener :one
+SELECT
+    COALESCE(MAX(events.time), 0)::BIGINT AS last_time
+FROM
+    public.events
+    JOIN public.event_gardeners ON event_gardeners.event = events.id
+WHERE event_gardeners.gardener = $1
+`
+
+func (q *Queries) GetLastEventTimeForGardener(ctx context.Context, gardener int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getLastEventTimeForGardener, gardener)
+	var last_time int64
+	err := row.Scan(&last_time)
+	return last_time, err
+}
+
+const listUpcomingEvents = `-- name: ListUpcomingEvents :many
+SELECT
+    id, name, time, type, hours, message_id, channel_id, scheduled_event_id
+FROM
+    public.events
+WHERE time >= $1
+ORDER BY time
+`
+
+func (q *Queries) ListUpcomingEvents(ctx context.Context, time int64) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listUpcomingEvents, time)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Time,
+			&i.Type,
+			&i.Hours,
+			&i.MessageID,
+			&i.ChannelID,
+			&i.ScheduledEventID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEvent = `-- name: LockEvent :one
+SELECT
+    id
+FROM
+    public.events
+WHERE
+    id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockEvent(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, lockEvent, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const trackLegacyEvent = `-- name: TrackLegacyEvent :one
+UPDATE public.events
+SET message_id = $5, channel_id = $6
+WHERE id = (
+    SELECT
+        id
+    FROM
+        public.events
+    WHERE message_id IS NULL
+    AND name = $1
+    AND time = $2
+    AND type = $3
+    AND hours = $4
+    ORDER BY id
+    LIMIT 1
+)
+AND message_id IS NULL
+RETURNING id, name, time, type, hours, message_id, channel_id, scheduled_event_id
+`
+
+type TrackLegacyEventParams struct {
+	Name      string
+	Time      int64
+	Type      EventType
+	Hours     int16
+	MessageID pgtype.Int8
+	ChannelID pgtype.Int8
+}
+
+func (q *Queries) TrackLegacyEvent(ctx context.Context, arg TrackLegacyEventParams) (Event, error) {
+	row := q.db.QueryRow(ctx, trackLegacyEvent,
+		arg.Name,
+		arg.Time,
+		arg.Type,
+		arg.Hours,
+		arg.MessageID,
+		arg.ChannelID,
+	)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Time,
+		&i.Type,
+		&i.Hours,
+		&i.MessageID,
+		&i.ChannelID,
+		&i.ScheduledEventID,
+	)
+	return i, err
+}
+
+const updateEvent = `-- name: UpdateEvent :exec
+UPDATE public.events
+SET name = $2, time = $3, hours = $4
+WHERE id = $1
+`
+
+type UpdateEventParams struct {
+	ID    int64
+	Name  string
+	Time  int64
+	Hours int16
+}
+
+func (q *Queries) UpdateEvent(ctx context.Context, arg UpdateEventParams) error {
+	_, err := q.db.Exec(ctx, updateEvent,
+		arg.ID,
+		arg.Name,
+		arg.Time,
+		arg.Hours,
+	)
+	return err
+}
@@ -7,6 +7,8 @@ package sqlc
 import (
 	"database/sql/driver"
 	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type EventType string
@@ -99,16 +101,132 @@ func (ns NullScoreboardGame) Value() (driver.Value, error) {
 }
 
 type Event struct {
-	ID       int64
-	Name     string
-	Time     int64
-	Type     EventType
+	ID               int64
+	Name             string
+	Time             int64
+	Type             EventType
+	Hours            int16
+	MessageID        pgtype.Int8
+	ChannelID        pgtype.Int8
+	ScheduledEventID pgtype.Int8
+}
+
+type EventGardener struct {
+	Event    int64
 	Gardener int64
-	Hours    int16
+}
+
+type EventReminder struct {
+	Event         int64
+	Kind          string
+	OffsetSeconds int32
+	EventTime     int64
+	SentAt        pgtype.Timestamptz
+	Recipient     int64
+	Attempts      int16
+}
+
+type Gardener struct {
+	ID     int64
+	Name   string
+	Active bool
+}
+
+type HourlyRate struct {
+	ID            int64
+	Gardener      pgtype.Int8
+	Type          NullEventType
+	RateCents     int32
+	EffectiveFrom int64
+}
+
+type Lockdown struct {
+	ID                int64
+	GuildID           int64
+	VerificationLevel int32
+	StartedAt         int64
+	EndedAt           pgtype.Int8
+	EndedBy           pgtype.Int8
+}
+
+type MatchDraft struct {
+	ID        int64
+	Wiki      string
+	MatchID   string
+	Type      EventType
+	Name      string
+	Time      int64
+	Hours     int16
+	Status    string
+	MessageID pgtype.Int8
+	ChannelID pgtype.Int8
+}
+
+type MemberJoin struct {
+	GuildID  int64
+	UserID   int64
+	JoinedAt int64
+}
+
+type ModCase struct {
+	ID          int64
+	GuildID     int64
+	UserID      int64
+	Action      string
+	ModeratorID pgtype.Int8
+	Reason      string
+	Evidence    pgtype.Text
+	CreatedAt   int64
+}
+
+type Prediction struct {
+	Match     int64
+	Member    int64
+	Scoreline string
+}
+
+type PredictionMatch struct {
+	ID              int64
+	Name            string
+	Game            ScoreboardGame
+	SeriesLength    int16
+	Deadline        int64
+	Result          pgtype.Text
+	MessageID       pgtype.Int8
+	ChannelID       pgtype.Int8
+	Opponent        pgtype.Text
+	Wiki            pgtype.Text
+	LiquipediaMatch pgtype.Text
+	ProposedResult  pgtype.Text
 }
 
 type Scoreboard struct {
 	Member int64
 	Score  int16
 	Game   ScoreboardGame
+	Season int64
+}
+
+type Season struct {
+	ID        int64
+	Name      string
+	StartedAt pgtype.Timestamptz
+	EndedAt   pgtype.Timestamptz
+}
+
+type SpamIncident struct {
+	ID        int64
+	GuildID   int64
+	AuthorID  int64
+	Status    string
+	CreatedAt int64
+}
+
+type SpamMessage struct {
+	MessageID int64
+	ChannelID int64
+	AuthorID  int64
+	Content   string
+	CreatedAt int64
+	Incident  int64
 }
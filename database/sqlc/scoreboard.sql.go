@@ -9,15 +9,6 @@ import (
 	"context"
 )
 
-const clearScoreboard = `-- name: ClearScoreboard :exec
-TRUNCATE TABLE public.scoreboards
-`
-
-func (q *Queries) ClearScoreboard(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, clearScoreboard)
-	return err
-}
-
 const getGlobalWinner = `-- name: GetGlobalWinner :many
 WITH
     GlobalRankedLeaderboard AS (
@@ -30,6 +21,8 @@ WITH
             sum(score) AS score
         FROM
             public.scoreboards
+        WHERE
+            season = $1
         GROUP BY
             member
     )
@@ -47,8 +40,8 @@ type GetGlobalWinnerRow struct {
 	Score    int64
 }
 
-func (q *Queries) GetGlobalWinner(ctx context.Context) ([]GetGlobalWinnerRow, error) {
-	rows, err := q.db.Query(ctx, getGlobalWinner)
+func (q *Queries) GetGlobalWinner(ctx context.Context, season int64) ([]GetGlobalWinnerRow, error) {
+	rows, err := q.db.Query(ctx, getGlobalWinner, season)
 	if err != nil {
 		return nil, err
 	}
@@ -69,28 +62,39 @@ func (q *Queries) GetGlobalWinner(ctx context.Context) ([]GetGlobalWinnerRow, er
 
 const getMemberGlobalScore = `-- name: GetMemberGlobalScore :one
 SELECT
-    DENSE_RANK() OVER (
-        ORDER BY
-            sum(score) DESC
-    ) AS position,
-    member,
-    sum(score) AS score
-FROM
-    public.scoreboards
-GROUP BY
-    member
-HAVING
+    position, member, score
+FROM (
+    SELECT
+        DENSE_RANK() OVER (
+            ORDER BY
+                sum(score) DESC
+        ) AS position,
+        member,
+        sum(score) AS score
+    FROM
+        public.scoreboards
+    WHERE
+        season = $2
+    GROUP BY
+        member
+)
+WHERE
     member = $1
 `
 
+type GetMemberGlobalScoreParams struct {
+	Member int64
+	Season int64
+}
+
 type GetMemberGlobalScoreRow struct {
 	Position int64
 	Member   int64
 	Score    int64
 }
 
-func (q *Queries) GetMemberGlobalScore(ctx context.Context, member int64) (GetMemberGlobalScoreRow, error) {
-	row := q.db.QueryRow(ctx, getMemberGlobalScore, member)
+func (q *Queries) GetMemberGlobalScore(ctx context.Context, arg GetMemberGlobalScoreParams) (GetMemberGlobalScoreRow, error) {
+	row := q.db.QueryRow(ctx, getMemberGlobalScore, arg.Member, arg.Season)
 	var i GetMemberGlobalScoreRow
 	err := row.Scan(&i.Position, &i.Member, &i.Score)
 	return i, err
@@ -98,22 +102,29 @@ func (q *Queries) GetMemberGlobalScore(ctx context.Context, member int64) (GetMe
 
 const getMemberScoreForGame = `-- name: GetMemberScoreForGame :one
 SELECT
-    DENSE_RANK() OVER (
-        ORDER BY
-            score DESC
-    ) position,
-    member,
-    score
-FROM
-    public.scoreboards
+    position, member, score
+FROM (
+    SELECT
+        DENSE_RANK() OVER (
+            ORDER BY
+                score DESC
+        ) position,
+        member,
+        score
+    FROM
+        public.scoreboards
+    WHERE
+        game = $1
+        AND season = $3
+)
 WHERE
-    game = $1
-    AND member = $2
+    member = $2
 `
 
 type GetMemberScoreForGameParams struct {
 	Game   ScoreboardGame
 	Member int64
+	Season int64
 }
 
 type GetMemberScoreForGameRow struct {
@@ -123,7 +134,7 @@ type GetMemberScoreForGameRow struct {
 }
 
 func (q *Queries) GetMemberScoreForGame(ctx context.Context, arg GetMemberScoreForGameParams) (GetMemberScoreForGameRow, error) {
-	row := q.db.QueryRow(ctx, getMemberScoreForGame, arg.Game, arg.Member)
+	row := q.db.QueryRow(ctx, getMemberScoreForGame, arg.Game, arg.Member, arg.Season)
 	var i GetMemberScoreForGameRow
 	err := row.Scan(&i.Position, &i.Member, &i.Score)
 	return i, err
@@ -144,19 +155,25 @@ FROM (
         public.scoreboards
     WHERE
         game = $1
+        AND season = $2
 )
 WHERE
     position = 1
 `
 
+type GetWinnerForGameParams struct {
+	Game   ScoreboardGame
+	Season int64
+}
+
 type GetWinnerForGameRow struct {
 	Position int64
 	Member   int64
 	Score    int16
 }
 
-func (q *Queries) GetWinnerForGame(ctx context.Context, game ScoreboardGame) ([]GetWinnerForGameRow, error) {
-	rows, err := q.db.Query(ctx, getWinnerForGame, game)
+func (q *Queries) GetWinnerForGame(ctx context.Context, arg GetWinnerForGameParams) ([]GetWinnerForGameRow, error) {
+	rows, err := q.db.Query(ctx, getWinnerForGame, arg.Game, arg.Season)
 	if err != nil {
 		return nil, err
 	}
@@ -185,8 +202,13 @@ SELECT
     sum(score) AS score
 FROM
     public.scoreboards
+WHERE
+    season = $1
 GROUP BY
     member
+ORDER BY
+    position,
+    member
 `
 
 type ShowGlobalScoreboardRow struct {
@@ -195,8 +217,8 @@ type ShowGlobalScoreboardRow struct {
 	Score    int64
 }
 
-func (q *Queries) ShowGlobalScoreboard(ctx context.Context) ([]ShowGlobalScoreboardRow, error) {
-	rows, err := q.db.Query(ctx, showGlobalScoreboard)
+func (q *Queries) ShowGlobalScoreboard(ctx context.Context, season int64) ([]ShowGlobalScoreboardRow, error) {
+	rows, err := q.db.Query(ctx, showGlobalScoreboard, season)
 	if err != nil {
 		return nil, err
 	}
@@ -227,16 +249,25 @@ FROM
     public.scoreboards
 WHERE
     game = $1
+    AND season = $2
+ORDER BY
+    position,
+    member
 `
 
+type ShowScoreboardForGameParams struct {
+	Game   ScoreboardGame
+	Season int64
+}
+
 type ShowScoreboardForGameRow struct {
 	Position int64
 	Member   int64
 	Score    int16
 }
 
-func (q *Queries) ShowScoreboardForGame(ctx context.Context, game ScoreboardGame) ([]ShowScoreboardForGameRow, error) {
-	rows, err := q.db.Query(ctx, showScoreboardForGame, game)
+func (q *Queries) ShowScoreboardForGame(ctx context.Context, arg ShowScoreboardForGameParams) ([]ShowScoreboardForGameRow, error) {
+	rows, err := q.db.Query(ctx, showScoreboardForGame, arg.Game, arg.Season)
 	if err != nil {
 		return nil, err
 	}
@@ -257,9 +288,21 @@ func (q *Queries) ShowScoreboardForGame(ctx context.Context, game ScoreboardGame
 
 const updateScoreboardForGame = `-- name: UpdateScoreboardForGame :exec
 INSERT INTO
-    public.scoreboards (member, score, game)
+    public.scoreboards (member, score, game, season)
 VALUES
-    ($1, 1, $2) ON CONFLICT ON CONSTRAINT scoreboards_pkey DO
+    (
+        $1,
+        1,
+        $2,
+        (
+            SELECT
+                id
+            FROM
+                public.seasons
+            WHERE
+                ended_at IS NULL
+        )
+    ) ON CONFLICT ON CONSTRAINT scoreboards_pkey DO
 UPDATE
 SET
     score = scoreboards.score + 1
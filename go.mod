@@ -6,6 +6,7 @@ require (
 	github.com/disgoorg/disgo v0.19.0-rc.14
 	github.com/disgoorg/omit v1.0.0
 	github.com/disgoorg/snowflake/v2 v2.0.3
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/jackc/pgx/v5 v5.8.0
 	github.com/olekukonko/tablewriter v1.1.2
 	github.com/pelletier/go-toml/v2 v2.2.4
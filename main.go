@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -11,9 +12,11 @@ import (
 
 	"clockey/app"
 	"clockey/app/commands"
+	"clockey/app/commands/moderation"
 	"clockey/app/commands/predictions"
 	"clockey/app/commands/signups"
 	"clockey/app/commands/utils"
+	"clockey/database/migrations"
 	"clockey/database/sqlc"
 
 	"github.com/disgoorg/disgo/bot"
@@ -45,37 +48,97 @@ func main() {
 	defer conn.Close()
 
 	setupLogger(cfg.Log)
+
+	if flag.Arg(0) == "migrate" {
+		if err = migrate(conn, flag.Args()[1:]); err != nil {
+			slog.Error("Failed to run migrations", slog.Any("err", err))
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+		return
+	}
+
 	slog.Info("Starting Clockey...", slog.String("version", Version), slog.String("commit", Commit))
 
+	if cfg.Database.AutoMigrate {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		applied, err := migrations.Up(ctx, conn)
+		cancel()
+		for _, migration := range applied {
+			slog.Info("Applied migration", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+		}
+		if err != nil {
+			slog.Error("Failed to migrate database", slog.Any("err", err))
+			os.Exit(-1)
+		}
+	}
+
 	b := app.New(*cfg, Version, Commit, app.Database{
 		Queries: sqlc.New(conn),
 		Conn:    conn,
 	})
 
 	h := handler.New()
+	h.Use(b.RequireRoles)
 	// Signups
 	h.MessageCommand("/Cancel Event", signups.CancelCommandHandler(b))
+	h.ButtonComponent("/cancel/{messageID}/{action}", signups.CancelComponentHandler(b))
+	h.ButtonComponent("/draft/{draft}/{action}", signups.DraftComponentHandler(b))
 	h.SlashCommand("/edit", signups.EditCommandHandler(b))
-	h.SlashCommand("/event", signups.EventCommandHandler())
+	h.SlashCommand("/event", signups.EventCommandHandler(b))
+	h.Modal("/event", signups.EventModalHandler(b))
 	h.MessageCommand("/Roll Gardener", signups.GardenerCommandHandler(b))
+	h.SelectMenuComponent("/roll/{messageID}", signups.GardenerComponentHandler(b))
+	h.ButtonComponent("/roll/{messageID}/auto", signups.RollComponentHandler(b))
 	h.SlashCommand("/manual", signups.ManualCommandHandler(b))
+	h.Autocomplete("/manual", signups.ManualAutocompleteHandler(b))
+	h.Modal("/manual/{gardener}", signups.ManualModalHandler(b))
+	h.SlashCommand("/myhours", signups.MyHoursCommandHandler(b))
 	h.SlashCommand("/report", signups.ReportCommandHandler(b))
+	h.ButtonComponent("/report/{gardenerID}/{start}/{end}", signups.ReportComponentHandler(b))
+	h.Route("/gardener", func(r handler.Router) {
+		r.SlashCommand("/add", signups.RosterAddCommandHandler(b))
+		r.SlashCommand("/remove", signups.RosterRemoveCommandHandler(b))
+		r.SlashCommand("/rename", signups.RosterRenameCommandHandler(b))
+		r.SlashCommand("/list", signups.RosterListCommandHandler(b))
+	})
+	h.Route("/rate", func(r handler.Router) {
+		r.SlashCommand("/set", signups.RateSetCommandHandler(b))
+		r.SlashCommand("/list", signups.RateListCommandHandler(b))
+		r.SlashCommand("/remove", signups.RateRemoveCommandHandler(b))
+	})
 	// Predictions
-	h.SlashCommand("/add", predictions.AddCommandHandler(b))
-	h.SlashCommand("/bo", predictions.BestOfCommandHandler())
-	h.Autocomplete("/bo", predictions.BestOfAutocompleteHandler())
-	h.SlashCommand("/deletebo", predictions.DeleteBestOfCommandHandler())
-	h.Autocomplete("/deletebo", predictions.BestOfAutocompleteHandler())
+	h.SlashCommand("/bo", predictions.BestOfCommandHandler(b))
+	h.Autocomplete("/bo", predictions.BestOfMatchAutocompleteHandler(b))
+	h.ButtonComponent("/award/{match}/{scoreline}", predictions.AwardComponentHandler(b))
+	h.SelectMenuComponent("/award/{match}", predictions.AwardSelectHandler(b))
+	h.ButtonComponent("/predict/{match}/{scoreline}", predictions.PredictComponentHandler(b))
 	h.SlashCommand("/reset", predictions.ResetCommandHandler(b))
+	h.ButtonComponent("/reset/{season}/{action}", predictions.ResetComponentHandler(b))
+	h.SlashCommand("/resolve", predictions.ResolveCommandHandler(b))
+	h.Autocomplete("/resolve", predictions.ResolveAutocompleteHandler(b))
 	h.SlashCommand("/show", predictions.ShowCommandHandler(b))
+	h.Autocomplete("/show", predictions.ShowAutocompleteHandler(b))
+	h.ButtonComponent("/show/{season}/{game}/{page}/{direction}", predictions.ShowComponentHandler(b))
 	h.SlashCommand("/winners", predictions.WinnersCommandHandler(b))
+	// Moderation
+	h.Route("/case", func(r handler.Router) {
+		r.SlashCommand("/view", moderation.CaseViewCommandHandler(b))
+		r.SlashCommand("/list", moderation.CaseListCommandHandler(b))
+	})
+	h.Route("/raid", func(r handler.Router) {
+		r.SlashCommand("/end", moderation.RaidEndCommandHandler(b))
+		r.ButtonComponent("/{lockdown}/end", moderation.RaidComponentHandler(b))
+	})
+	h.ButtonComponent("/spam/{incident}/{action}", moderation.SpamComponentHandler(b))
+	h.SlashCommand("/warn", moderation.WarnCommandHandler(b))
 	// Utils
 	h.SlashCommand("/util", utils.UtilCommandHandler())
 	// Other
 	h.SlashCommand("/ping", commands.PingCommandHandler())
-	h.SlashCommand("/next", commands.NextCommandHandler())
+	h.SlashCommand("/next", commands.NextCommandHandler(b))
 
-	if err = b.SetupBot(h, bot.NewListenerFunc(b.OnReady), bot.NewListenerFunc(b.OnCommand), bot.NewListenerFunc(b.OnModal), bot.NewListenerFunc(b.OnMessageCreate)); err != nil {
+	if err = b.SetupBot(h, bot.NewListenerFunc(b.OnReady), bot.NewListenerFunc(b.OnCommand), bot.NewListenerFunc(b.OnModal), bot.NewListenerFunc(moderation.HoneypotListener(b)), bot.NewListenerFunc(moderation.SpamListener(b)), bot.NewListenerFunc(moderation.RaidListener(b)), bot.NewListenerFunc(moderation.BanListener(b))); err != nil {
 		slog.Error("Failed to setup bot", slog.Any("err", err))
 		os.Exit(-1)
 	}
@@ -101,6 +164,12 @@ func main() {
 		os.Exit(-1)
 	}
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go signups.WatchMatches(jobCtx, b)
+	go signups.WatchReminders(jobCtx, b)
+	go predictions.WatchResults(jobCtx, b)
+
 	slog.Info("Bot is running. Press CTRL-C to exit.")
 	s := make(chan os.Signal, 1)
 	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM)
@@ -108,6 +177,54 @@ func main() {
 	slog.Info("Shutting down bot...")
 }
 
+// migrate handles `clockey migrate up|down|status`.
+func migrate(conn *pgxpool.Pool, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: clockey [flags] migrate up|down|status")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(ctx, conn)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+	case "down":
+		reverted, ok, err := migrations.Down(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("no migrations to revert")
+			return nil
+		}
+		fmt.Printf("reverted %04d_%s\n", reverted.Version, reverted.Name)
+	case "status":
+		statuses, err := migrations.List(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+	return nil
+}
+
 func setupLogger(cfg app.LogConfig) {
 	opts := &slog.HandlerOptions{
 		AddSource: cfg.AddSource,
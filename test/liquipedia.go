@@ -0,0 +1,40 @@
+package test
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+//go:embed fixtures/liquipedia/*.json
+var liquipediaFixtures embed.FS
+
+// NewLiquipediaServer serves the recorded match2 responses in
+// fixtures/liquipedia, picked by wiki and whether finished matches were
+// requested. Wikis without a fixture return no matches.
+func NewLiquipediaServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Apikey ") {
+			http.Error(w, `{"error":["missing api key"]}`, http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/match" {
+			http.NotFound(w, r)
+			return
+		}
+
+		kind := "upcoming"
+		if strings.Contains(r.URL.Query().Get("conditions"), "[[finished::1]]") {
+			kind = "finished"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		data, err := liquipediaFixtures.ReadFile("fixtures/liquipedia/" + r.URL.Query().Get("wiki") + "_" + kind + ".json")
+		if err != nil {
+			_, _ = w.Write([]byte(`{"result":[]}`))
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+}